@@ -0,0 +1,96 @@
+package agentmesh
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the API while the circuit
+// breaker is open after repeated failures
+var ErrCircuitOpen = errors.New("agentmesh: circuit breaker open")
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen after
+// threshold consecutive request attempts fail with a connection error or a
+// 5xx response. After cooldown a single attempt is let through to probe
+// the API: if it succeeds the breaker closes, otherwise it stays open for
+// another cooldown.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Config) {
+		c.CircuitBreakerThreshold = threshold
+		c.CircuitBreakerCooldown = cooldown
+	}
+}
+
+// circuitBreaker counts consecutive failed attempts. A nil breaker allows
+// everything.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	// probing is set while the attempt let through after a cooldown is in
+	// flight
+	probing bool
+}
+
+// allow reports whether an attempt may be made. Once the cooldown of an
+// open breaker has passed it lets one attempt through and holds others off
+// for another cooldown, so a probe that never finishes can't keep it
+// closed to everyone.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+	now := time.Now()
+	if now.Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+	b.openUntil = now.Add(b.cooldown)
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of an attempt
+func (b *circuitBreaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// release gives back an attempt that allow let through but that ended
+// without an outcome, such as when the caller cancelled it. If it was the
+// probe of an open breaker, the next attempt may probe right away rather
+// than after another cooldown.
+func (b *circuitBreaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.probing {
+		b.probing = false
+		b.openUntil = time.Time{}
+	}
+}
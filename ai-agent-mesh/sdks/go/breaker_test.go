@@ -0,0 +1,124 @@
+package agentmesh
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+
+	// Each step either asks allow, records an outcome, releases, or waits
+	// out the cooldown
+	type step struct {
+		op   string
+		want error
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"closed below threshold", []step{
+			{"fail", nil}, {"allow", nil}, {"fail", nil}, {"allow", nil},
+		}},
+		{"success resets the count", []step{
+			{"fail", nil}, {"fail", nil}, {"succeed", nil}, {"fail", nil}, {"fail", nil}, {"allow", nil},
+		}},
+		{"opens at threshold", []step{
+			{"fail", nil}, {"fail", nil}, {"fail", nil}, {"allow", ErrCircuitOpen},
+		}},
+		{"one probe after cooldown", []step{
+			{"fail", nil}, {"fail", nil}, {"fail", nil}, {"wait", nil},
+			{"allow", nil}, {"allow", ErrCircuitOpen},
+		}},
+		{"successful probe closes", []step{
+			{"fail", nil}, {"fail", nil}, {"fail", nil}, {"wait", nil},
+			{"allow", nil}, {"succeed", nil}, {"allow", nil}, {"allow", nil},
+		}},
+		{"failed probe reopens", []step{
+			{"fail", nil}, {"fail", nil}, {"fail", nil}, {"wait", nil},
+			{"allow", nil}, {"fail", nil}, {"allow", ErrCircuitOpen},
+		}},
+		{"released probe can be retried at once", []step{
+			{"fail", nil}, {"fail", nil}, {"fail", nil}, {"wait", nil},
+			{"allow", nil}, {"release", nil}, {"allow", nil}, {"allow", ErrCircuitOpen},
+		}},
+		{"release while closed is a no-op", []step{
+			{"allow", nil}, {"release", nil}, {"fail", nil}, {"fail", nil}, {"fail", nil}, {"allow", ErrCircuitOpen},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &circuitBreaker{threshold: 3, cooldown: cooldown}
+			for i, s := range tt.steps {
+				switch s.op {
+				case "allow":
+					assert.Equal(t, s.want, b.allow(), "step %d", i)
+				case "fail":
+					b.record(true)
+				case "succeed":
+					b.record(false)
+				case "release":
+					b.release()
+				case "wait":
+					time.Sleep(cooldown + 5*time.Millisecond)
+				}
+			}
+		})
+	}
+
+	t.Run("nil breaker allows everything", func(t *testing.T) {
+		var b *circuitBreaker
+		b.record(true)
+		b.release()
+		assert.NoError(t, b.allow())
+	})
+}
+
+func TestCircuitBreakerProbeNotLeaked(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"id":"agent_1"}`))
+	}))
+	defer srv.Close()
+
+	var credentialErr atomic.Bool
+	provider := CredentialProviderFunc(func(ctx context.Context) (string, error) {
+		if credentialErr.Load() {
+			return "", errors.New("vault unavailable")
+		}
+		return "token", nil
+	})
+	client := NewClient("", WithBaseURL(srv.URL), WithCredentialProvider(provider),
+		WithCircuitBreaker(1, 20*time.Millisecond))
+	ctx := context.Background()
+
+	_, err := client.Agents.Get(ctx, "agent_1")
+	require.ErrorIs(t, err, ErrServer)
+	_, err = client.Agents.Get(ctx, "agent_1")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	time.Sleep(25 * time.Millisecond)
+
+	// The probe fails before it is sent; the breaker must not wait for it
+	credentialErr.Store(true)
+	_, err = client.Agents.Get(ctx, "agent_1")
+	require.ErrorContains(t, err, "vault unavailable")
+
+	credentialErr.Store(false)
+	failing.Store(false)
+	_, err = client.Agents.Get(ctx, "agent_1")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,115 @@
+package agentmesh
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// CacheEntry is a cached GET response body and the ETag it was served with
+type CacheEntry struct {
+	ETag string
+	Body []byte
+}
+
+// Cache stores GET responses for revalidation with If-None-Match.
+// Implementations must be safe for concurrent use; a shared store such as
+// Redis can be plugged in by implementing it.
+type Cache interface {
+	Get(ctx context.Context, key string) (*CacheEntry, bool)
+	Set(ctx context.Context, key string, entry *CacheEntry)
+}
+
+// WithCache enables ETag-based caching of GET responses. Cached responses
+// are revalidated on every call and reused when the server answers
+// 304 Not Modified, so they are never stale but cost no response body.
+func WithCache(cache Cache) Option {
+	return func(c *Config) {
+		c.Cache = cache
+	}
+}
+
+// uncachedHeaders are per-request headers that don't affect the response,
+// such as IDs that differ between otherwise identical calls, and so are
+// left out of cache keys
+var uncachedHeaders = map[string]bool{
+	http.CanonicalHeaderKey(correlationIDHeader): true,
+	"Idempotency-Key": true,
+}
+
+// cacheKey identifies a GET request's response. The credentials and any
+// per-request headers are part of the key so that callers sharing a cache,
+// such as different tenants, never see each other's data.
+func cacheKey(req *http.Request, header http.Header) string {
+	h := sha256.New()
+	h.Write([]byte(req.Header.Get("Authorization")))
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		if !uncachedHeaders[http.CanonicalHeaderKey(key)] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(h, "\x00%s=%q", key, header[key])
+	}
+	return req.URL.String() + "#" + hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// MemoryCache is an in-memory Cache holding up to a fixed number of
+// entries, evicting the least recently used
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns a MemoryCache holding up to maxEntries responses;
+// zero or less means unbounded
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// Get implements Cache
+func (m *MemoryCache) Get(_ context.Context, key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(el)
+	return el.Value.(*memoryCacheItem).entry, true
+}
+
+// Set implements Cache
+func (m *MemoryCache) Set(_ context.Context, key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[key]; ok {
+		el.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(el)
+		return
+	}
+	m.entries[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	if m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
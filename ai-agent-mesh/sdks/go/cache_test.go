@@ -0,0 +1,35 @@
+package agentmesh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheIgnoresCorrelationID(t *testing.T) {
+	var revalidated int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"id":"agent_1"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithCache(NewMemoryCache(0)))
+	for _, id := range []string{"trace-1", "trace-2"} {
+		ctx := WithCorrelationID(context.Background(), id)
+		agent, err := client.Agents.Get(ctx, "agent_1")
+		require.NoError(t, err)
+		assert.Equal(t, "agent_1", agent.ID)
+	}
+
+	assert.Equal(t, 1, revalidated)
+}
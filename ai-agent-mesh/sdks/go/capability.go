@@ -0,0 +1,95 @@
+package agentmesh
+
+import "fmt"
+
+// Capability is something a federated agent can do, used to match agents
+// in discovery. The federation types hold capabilities as plain strings;
+// convert the constants with string(CapabilityNLP).
+type Capability string
+
+// Well-known capabilities
+const (
+	CapabilityConversational Capability = "conversational"
+	CapabilityNLP            Capability = "nlp"
+	CapabilityVision         Capability = "vision"
+	CapabilityEmbedding      Capability = "embedding"
+	CapabilitySummarization  Capability = "summarization"
+	CapabilityTranslation    Capability = "translation"
+	CapabilityClassification Capability = "classification"
+	CapabilityCodeGeneration Capability = "code_generation"
+	CapabilitySpeech         Capability = "speech"
+)
+
+// knownCapabilities are accepted by Register, UpdateConfig and Discover
+// without a warning
+var knownCapabilities = map[Capability]bool{
+	CapabilityConversational: true,
+	CapabilityNLP:            true,
+	CapabilityVision:         true,
+	CapabilityEmbedding:      true,
+	CapabilitySummarization:  true,
+	CapabilityTranslation:    true,
+	CapabilityClassification: true,
+	CapabilityCodeGeneration: true,
+	CapabilitySpeech:         true,
+}
+
+// WithCustomCapabilities declares capabilities beyond the well-known ones
+// that the federation methods should accept without a warning
+func WithCustomCapabilities(capabilities ...Capability) Option {
+	return func(c *Config) {
+		c.CustomCapabilities = append(c.CustomCapabilities, capabilities...)
+	}
+}
+
+// WithStrictCapabilities makes the federation methods reject capabilities
+// that are neither well-known nor declared with WithCustomCapabilities
+// with a *ValidationError before a request is sent, instead of logging a
+// warning, so misspellings can't silently break discovery
+func WithStrictCapabilities() Option {
+	return func(c *Config) {
+		c.StrictCapabilities = true
+	}
+}
+
+// checkCapabilities warns about every capability that is neither
+// well-known nor declared as custom, or with strict checking returns a
+// *ValidationError naming them
+func (s *FederationService) checkCapabilities(capabilities []string) error {
+	f := fieldErrors{}
+	for i, name := range capabilities {
+		if knownCapabilities[Capability(name)] || s.customCapabilities[Capability(name)] {
+			continue
+		}
+		if !s.strictCapabilities {
+			if s.client.logger != nil {
+				s.client.logger.Warn("agentmesh: unknown capability; check its spelling or declare it with WithCustomCapabilities", "capability", name)
+			}
+			continue
+		}
+		f[fmt.Sprintf("capabilities[%d]", i)] = fmt.Sprintf("unknown capability %q", name)
+	}
+	return f.err("capabilities")
+}
+
+// capabilitiesFrom extracts the capabilities of a register config map,
+// which may hold them as []string, []Capability or decoded JSON
+func capabilitiesFrom(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []Capability:
+		names := make([]string, len(v))
+		for i, capability := range v {
+			names[i] = string(capability)
+		}
+		return names
+	case []interface{}:
+		names := make([]string, len(v))
+		for i, item := range v {
+			names[i] = fmt.Sprint(item)
+		}
+		return names
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package agentmesh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownCapabilities(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	opts := &DiscoverOptions{Capabilities: []string{string(CapabilityNLP), "embeddings"}}
+
+	t.Run("warns by default", func(t *testing.T) {
+		var logs bytes.Buffer
+		client := NewClient("test-key", WithBaseURL(srv.URL), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+		_, err := client.Federation.Discover(context.Background(), opts)
+
+		require.NoError(t, err)
+		assert.Contains(t, logs.String(), "capability=embeddings")
+	})
+
+	t.Run("warning without a logger doesn't panic", func(t *testing.T) {
+		client := NewClient("test-key", WithBaseURL(srv.URL))
+		require.Nil(t, client.logger)
+
+		assert.NotPanics(t, func() {
+			_, err := client.Federation.Discover(context.Background(), opts)
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("rejected when strict", func(t *testing.T) {
+		hits = 0
+		client := NewClient("test-key", WithBaseURL(srv.URL), WithStrictCapabilities())
+		_, err := client.Federation.Discover(context.Background(), opts)
+
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr), "got %v", err)
+		assert.Contains(t, validationErr.Fields, "capabilities[1]")
+		assert.Zero(t, hits)
+	})
+
+	t.Run("custom capabilities are accepted", func(t *testing.T) {
+		client := NewClient("test-key", WithBaseURL(srv.URL), WithStrictCapabilities(), WithCustomCapabilities("embeddings"))
+		_, err := client.Federation.Discover(context.Background(), opts)
+
+		assert.NoError(t, err)
+	})
+}
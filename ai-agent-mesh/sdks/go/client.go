@@ -4,11 +4,24 @@ package agentmesh
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -16,13 +29,74 @@ const (
 	DefaultBaseURL = "https://api.ai-agent-mesh.com/v3"
 	// SDKVersion is the current SDK version
 	SDKVersion = "3.0.0"
+	// DefaultPollInterval is how often polling helpers check for progress
+	DefaultPollInterval = 2 * time.Second
+)
+
+// Regions accepted by WithRegion
+const (
+	RegionUS   = "us"
+	RegionEU   = "eu"
+	RegionAPAC = "apac"
 )
 
-// Client is the main AI-Agent Mesh SDK client
+// Protocols accepted by WithProtocol
+const (
+	// ProtocolAuto negotiates HTTP/2 with servers that support it and
+	// falls back to HTTP/1.1
+	ProtocolAuto = ""
+	// ProtocolHTTP1 always uses HTTP/1.1
+	ProtocolHTTP1 = "http/1.1"
+	// ProtocolHTTP2 requires HTTP/2, failing the TLS handshake with
+	// servers that don't offer it
+	ProtocolHTTP2 = "h2"
+)
+
+// regionalBaseURLFormat is DefaultBaseURL with the region in the host
+const regionalBaseURLFormat = "https://api.%s.ai-agent-mesh.com/v3"
+
+// Client is the main AI-Agent Mesh SDK client.
+//
+// A Client is safe for concurrent use by multiple goroutines and is meant
+// to be created once and shared. Its configuration is fixed by NewClient,
+// except for the API key which SetAPIKey can rotate; that and the other
+// state that changes afterwards (the latest rate-limit snapshot, cached
+// OAuth2 tokens and config schemas) is guarded internally. The service
+// fields must not be reassigned.
 type Client struct {
-	apiKey     string
+	// Set by NewClient and read-only afterwards
 	baseURL    string
 	httpClient *http.Client
+	timeout    time.Duration
+	maxRetries int
+	backoff    backoffPolicy
+	compress   bool
+
+	requestInterceptors  []RequestInterceptor
+	responseInterceptors []ResponseInterceptor
+	logger               *slog.Logger
+	userAgent            string
+
+	// credentials replaces the API key when set, e.g. with OAuth2 tokens
+	credentials CredentialProvider
+
+	validateConfigs bool
+	schemas         schemaCache
+	cache           Cache
+
+	correlationIDFrom func(ctx context.Context) string
+	limiter           *rate.Limiter
+	metrics           MetricsRecorder
+	breaker           *circuitBreaker
+	retryBudget       time.Duration
+	stats             clientStats
+	decode            decodeOptions
+	serviceTimeouts   map[Service]time.Duration
+
+	// mu guards apiKey and rateLimit
+	mu        sync.Mutex
+	apiKey    string
+	rateLimit *RateLimit
 	
 	// Resource managers
 	Agents       *AgentService
@@ -32,45 +106,194 @@ type Client struct {
 	Federation   *FederationService
 	Marketplace  *MarketplaceService
 	Account      *AccountService
+	Webhooks     *WebhookService
 }
 
 // Config holds configuration for the client
 type Config struct {
-	APIKey     string
-	BaseURL    string
+	APIKey string
+	// BaseURL overrides the API endpoint; empty selects DefaultBaseURL or
+	// the endpoint of Region
+	BaseURL string
+	// Region pins requests to a regional endpoint for data residency
+	Region     string
 	Timeout    time.Duration
 	MaxRetries int
+	// ServiceTimeouts overrides Timeout for the requests of individual
+	// services; see WithServiceTimeout
+	ServiceTimeouts map[Service]time.Duration
+	// BackoffInitial, BackoffMax, BackoffMultiplier and BackoffJitter
+	// shape the delays between retries; see WithBackoff
+	BackoffInitial    time.Duration
+	BackoffMax        time.Duration
+	BackoffMultiplier float64
+	BackoffJitter     bool
+	// RetryBudget caps the total time of a call including retries; see
+	// WithRetryBudget
+	RetryBudget time.Duration
+	// Compression enables gzip for responses and large request bodies
+	Compression          bool
+	RequestInterceptors  []RequestInterceptor
+	ResponseInterceptors []ResponseInterceptor
+	Logger               *slog.Logger
+	// UserAgent identifies the application; it is appended to the SDK's
+	// own User-Agent
+	UserAgent string
+	// HTTPClient replaces the default HTTP client. The connection pool
+	// settings below don't apply to a replaced client.
+	HTTPClient *http.Client
+	// Transport replaces the transport of the default HTTP client. The
+	// connection pool settings below don't apply to it.
+	Transport http.RoundTripper
+	// MaxIdleConns, MaxIdleConnsPerHost and IdleConnTimeout tune the
+	// connection pool of the default transport; zero keeps Go's defaults
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// Protocol selects the HTTP version of the default transport; see
+	// WithProtocol
+	Protocol string
+	// InsecureSkipVerify turns off TLS certificate verification in the
+	// default transport. For local development only; see
+	// WithInsecureSkipVerify.
+	InsecureSkipVerify bool
+	// IngestBatchSize is the most telemetry events sent per ingest request
+	IngestBatchSize int
+	// OAuth2 enables OAuth2 client-credentials authentication in place of
+	// the API key
+	OAuth2 *OAuth2Config
+	// CredentialProvider supplies the credential of each request in place
+	// of the API key; it takes precedence over OAuth2
+	CredentialProvider CredentialProvider
+	// ConfigSchemaValidation validates agent configs against the schema of
+	// their type before creating agents
+	ConfigSchemaValidation bool
+	// Cache enables ETag-based caching of GET responses
+	Cache Cache
+	// CorrelationIDFromContext extracts the correlation ID sent with each
+	// request from its context
+	CorrelationIDFromContext func(ctx context.Context) string
+	// RateLimit and RateBurst throttle outgoing requests client-side to
+	// RateLimit requests per second with bursts of up to RateBurst; zero
+	// disables throttling
+	RateLimit float64
+	RateBurst int
+	// Metrics receives an observation for every API call
+	Metrics MetricsRecorder
+	// CustomCapabilities are accepted by the federation methods in addition
+	// to the well-known capabilities
+	CustomCapabilities []Capability
+	// StrictCapabilities rejects unknown capabilities instead of logging a
+	// warning; see WithStrictCapabilities
+	StrictCapabilities bool
+	// CircuitBreakerThreshold and CircuitBreakerCooldown configure the
+	// circuit breaker; a zero threshold disables it. See WithCircuitBreaker.
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+	// DisallowUnknownFields and UseNumber configure how responses are
+	// decoded; see WithStrictDecoding and WithUseNumber
+	DisallowUnknownFields bool
+	UseNumber             bool
 }
 
 // NewClient creates a new AI-Agent Mesh client
 func NewClient(apiKey string, opts ...Option) *Client {
 	config := &Config{
 		APIKey:     apiKey,
-		BaseURL:    DefaultBaseURL,
 		Timeout:    30 * time.Second,
 		MaxRetries: 3,
+
+		BackoffInitial:    DefaultBackoffInitial,
+		BackoffMax:        DefaultBackoffMax,
+		BackoffMultiplier: DefaultBackoffMultiplier,
+		BackoffJitter:     true,
+		IngestBatchSize:   500,
 	}
 	
 	for _, opt := range opts {
 		opt(config)
 	}
+
+	// Timeouts are applied per request through the context so that a
+	// caller's longer deadline isn't cut short
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		transport := config.Transport
+		if transport == nil {
+			transport = newTransport(config)
+		}
+		httpClient = &http.Client{Transport: transport}
+		if config.Transport == nil && config.InsecureSkipVerify && config.Logger != nil {
+			config.Logger.Warn("agentmesh: TLS certificate verification is disabled; do not use in production")
+		}
+	}
 	
 	client := &Client{
-		apiKey:  config.APIKey,
-		baseURL: config.BaseURL,
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
+		baseURL:              baseURL(config),
+		timeout:              config.Timeout,
+		maxRetries:           config.MaxRetries,
+		compress:             config.Compression,
+		requestInterceptors:  config.RequestInterceptors,
+		responseInterceptors: config.ResponseInterceptors,
+		logger:               config.Logger,
+		userAgent:            userAgent(config.UserAgent),
+		httpClient:           httpClient,
 	}
 	
+	client.apiKey = config.APIKey
+	client.validateConfigs = config.ConfigSchemaValidation
+	client.cache = config.Cache
+	client.backoff = backoffPolicy{
+		initial:    config.BackoffInitial,
+		max:        config.BackoffMax,
+		multiplier: config.BackoffMultiplier,
+		jitter:     config.BackoffJitter,
+	}
+	client.retryBudget = config.RetryBudget
+	client.serviceTimeouts = make(map[Service]time.Duration, len(config.ServiceTimeouts))
+	for service, timeout := range config.ServiceTimeouts {
+		client.serviceTimeouts[service] = timeout
+	}
+	client.correlationIDFrom = config.CorrelationIDFromContext
+	client.metrics = config.Metrics
+	client.decode = decodeOptions{
+		disallowUnknownFields: config.DisallowUnknownFields,
+		useNumber:             config.UseNumber,
+	}
+	if config.CircuitBreakerThreshold > 0 {
+		client.breaker = &circuitBreaker{
+			threshold: config.CircuitBreakerThreshold,
+			cooldown:  config.CircuitBreakerCooldown,
+		}
+	}
+	if config.RateLimit > 0 {
+		burst := config.RateBurst
+		if burst < 1 {
+			burst = 1
+		}
+		client.limiter = rate.NewLimiter(rate.Limit(config.RateLimit), burst)
+	}
+	client.credentials = config.CredentialProvider
+	if client.credentials == nil && config.OAuth2 != nil {
+		client.credentials = &oauth2TokenSource{config: *config.OAuth2, httpClient: httpClient}
+	}
+
 	// Initialize services
 	client.Agents = &AgentService{client: client}
 	client.Workflows = &WorkflowService{client: client}
 	client.Policies = &PolicyService{client: client}
-	client.Telemetry = &TelemetryService{client: client}
-	client.Federation = &FederationService{client: client}
+	client.Telemetry = &TelemetryService{client: client, ingestBatchSize: config.IngestBatchSize}
+	client.Federation = &FederationService{
+		client:             client,
+		customCapabilities: map[Capability]bool{},
+		strictCapabilities: config.StrictCapabilities,
+	}
+	for _, capability := range config.CustomCapabilities {
+		client.Federation.customCapabilities[capability] = true
+	}
 	client.Marketplace = &MarketplaceService{client: client}
 	client.Account = &AccountService{client: client}
+	client.Webhooks = &WebhookService{client: client}
 	
 	return client
 }
@@ -85,7 +308,30 @@ func WithBaseURL(url string) Option {
 	}
 }
 
-// WithTimeout sets a custom timeout
+// WithRegion sends every request to the endpoint of region (RegionUS,
+// RegionEU or RegionAPAC), so data never passes through another region.
+// An explicit WithBaseURL takes precedence regardless of option order.
+func WithRegion(region string) Option {
+	return func(c *Config) {
+		c.Region = region
+	}
+}
+
+// baseURL resolves the API endpoint from an explicit base URL, the
+// configured region, or the global default, in that order
+func baseURL(config *Config) string {
+	if config.BaseURL != "" {
+		return config.BaseURL
+	}
+	if config.Region != "" {
+		return fmt.Sprintf(regionalBaseURLFormat, strings.ToLower(config.Region))
+	}
+	return DefaultBaseURL
+}
+
+// WithTimeout sets the timeout applied to each request attempt whose
+// context has no deadline. A context deadline always takes precedence,
+// whether it is shorter or longer than this timeout.
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Config) {
 		c.Timeout = timeout
@@ -99,75 +345,502 @@ func WithMaxRetries(maxRetries int) Option {
 	}
 }
 
-// request makes an HTTP request to the API
+// WithHTTPClient sets the HTTP client used to make requests. Connection
+// pool options have no effect on it; tune its transport directly instead.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTransport sets the transport used to make requests, e.g. to add
+// tracing or mutual TLS, while keeping the SDK's timeout and retry
+// handling. Connection pool options have no effect on it. WithHTTPClient
+// takes precedence.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+// WithConnectionPool tunes the connection pool of the default transport:
+// the total and per-host number of idle keep-alive connections kept open,
+// and how long an idle connection is kept before closing. Zero values keep
+// Go's defaults.
+func WithConnectionPool(maxIdleConns, maxIdleConnsPerHost int, idleConnTimeout time.Duration) Option {
+	return func(c *Config) {
+		c.MaxIdleConns = maxIdleConns
+		c.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		c.IdleConnTimeout = idleConnTimeout
+	}
+}
+
+// WithProtocol selects the HTTP version of the default transport:
+// ProtocolAuto, ProtocolHTTP1 or ProtocolHTTP2. Over HTTP/2 all concurrent
+// requests to the API are multiplexed on a single connection, which avoids
+// head-of-line blocking between them during bursts; the connection pool
+// options then matter little, as the pool rarely holds more than one
+// connection. HTTP/2 is only used over TLS, so it requires an https base
+// URL. Like the pool options, it has no effect with WithTransport or
+// WithHTTPClient.
+func WithProtocol(protocol string) Option {
+	return func(c *Config) {
+		c.Protocol = protocol
+	}
+}
+
+// newTransport returns a transport based on http.DefaultTransport with the
+// configured connection pool and protocol settings
+func newTransport(config *Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	if config.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	switch config.Protocol {
+	case ProtocolHTTP1:
+		// A non-nil empty map turns off HTTP/2 upgrades
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	case ProtocolHTTP2:
+		transport.ForceAttemptHTTP2 = true
+		tlsConfig := transport.TLSClientConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			if cs.NegotiatedProtocol != ProtocolHTTP2 {
+				return errors.New("agentmesh: server does not support HTTP/2")
+			}
+			return nil
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport
+}
+
+// WithInsecureSkipVerify turns off verification of the API's TLS
+// certificate, so the client can talk to a self-hosted mesh with a
+// self-signed certificate during local development.
+//
+// DEVELOPMENT ONLY. Without verification anyone on the network path can
+// impersonate the API and read the API key and all data sent. Never use it
+// against a production mesh; to trust a private CA, pass a transport with
+// its RootCAs to WithTransport instead. It has no effect with WithTransport
+// or WithHTTPClient, and a configured logger gets a warning when it is used.
+func WithInsecureSkipVerify() Option {
+	return func(c *Config) {
+		c.InsecureSkipVerify = true
+	}
+}
+
+// WithRateLimit throttles outgoing requests to rps requests per second,
+// allowing bursts of up to burst requests. Requests, including retries,
+// wait for their turn or until their context is done.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Config) {
+		c.RateLimit = rps
+		c.RateBurst = burst
+	}
+}
+
+// WithIngestBatchSize sets the most telemetry events TelemetryService.Ingest
+// sends per request; larger batches are split
+func WithIngestBatchSize(size int) Option {
+	return func(c *Config) {
+		c.IngestBatchSize = size
+	}
+}
+
+// WithUserAgent appends an application identifier such as "my-app/1.2"
+// to the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+	}
+}
+
+// userAgent builds the User-Agent header, e.g.
+// "agentmesh-go/3.0.0 (go1.22.1; linux/amd64) my-app/1.2"
+func userAgent(app string) string {
+	ua := fmt.Sprintf("agentmesh-go/%s (%s; %s/%s)", SDKVersion, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if app != "" {
+		ua += " " + app
+	}
+	return ua
+}
+
+// WithCompression enables gzip compression. Responses are requested
+// gzip-encoded and decompressed transparently, and request bodies larger
+// than a few kilobytes are sent gzip-encoded.
+func WithCompression() Option {
+	return func(c *Config) {
+		c.Compression = true
+	}
+}
+
+// Ping checks that the API is reachable at the configured base URL and
+// accepts the client's credentials, without side effects. It goes through
+// the same rate limiting, headers, stats and metrics as any other call but
+// is not retried, so it fails fast when the API is unavailable.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, http.MethodGet, "ping", nil, "application/json", nil, 0)
+}
+
+// SetAPIKey replaces the API key used to authenticate requests, e.g. when
+// a secrets manager rotates it. It is safe to call while requests are in
+// flight: each attempt made afterwards, including retries of earlier
+// calls, uses the new key, and open connections are kept.
+func (c *Client) SetAPIKey(apiKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apiKey = apiKey
+}
+
+// request makes an HTTP request to the API with body encoded as JSON,
+// retrying transient failures up to maxRetries times
 func (c *Client) request(ctx context.Context, method, endpoint string, body interface{}, result interface{}) error {
-	url := fmt.Sprintf("%s/%s", c.baseURL, endpoint)
-	
-	var reqBody io.Reader
+	var payload []byte
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+		payload = jsonData
 	}
-	
-	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	return c.requestRaw(ctx, method, endpoint, payload, "application/json", result)
+}
+
+// requestRaw is like request but sends payload as is with the given
+// content type, for endpoints that take non-JSON bodies such as multipart
+// uploads. The response is still decoded as JSON.
+func (c *Client) requestRaw(ctx context.Context, method, endpoint string, payload []byte, contentType string, result interface{}) error {
+	return c.call(ctx, method, endpoint, payload, contentType, result, c.maxRetries)
+}
+
+// call is requestRaw retrying transient failures up to maxRetries times
+func (c *Client) call(ctx context.Context, method, endpoint string, payload []byte, contentType string, result interface{}, maxRetries int) error {
+	url := fmt.Sprintf("%s/%s", c.baseURL, endpoint)
+
+	header := headerFrom(ctx).Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", contentType)
+	if id := c.correlationID(ctx); id != "" {
+		header.Set(correlationIDHeader, id)
+	}
+	if key := idempotencyKeyFrom(ctx); key != "" {
+		header.Set("Idempotency-Key", key)
+	}
+	if c.compress && len(payload) >= compressThreshold {
+		compressed, err := gzipPayload(payload)
+		if err != nil {
+			return fmt.Errorf("failed to compress request body: %w", err)
+		}
+		payload = compressed
+		header.Set("Content-Encoding", "gzip")
+	}
+
+	md := responseMetadataFrom(ctx)
+	if md == nil && c.metrics != nil {
+		md = &ResponseMetadata{}
+		ctx = WithResponseMetadata(ctx, md)
+	}
+	start := time.Now()
+	attempts, err := c.retry(ctx, method, url, payload, header, result, md, maxRetries)
+	if err != nil && attempts > 1 {
+		err = &RetryError{Attempts: attempts, Elapsed: time.Since(start), Err: err}
+	}
+	var status int
+	if md != nil {
+		status = md.StatusCode
+	}
+	c.stats.record(attempts, err)
+	c.observe(method, endpoint, status, start, attempts, err)
+	return err
+}
+
+// retry calls do until it succeeds, fails permanently or has been retried
+// maxRetries times, and returns the number of attempts made. md, if not
+// nil, is reset before each attempt so it reflects only the last one.
+func (c *Client) retry(ctx context.Context, method, url string, payload []byte, header http.Header, result interface{}, md *ResponseMetadata, maxRetries int) (int, error) {
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return attempt, fmt.Errorf("rate limiter: %w", err)
+			}
+		}
+		if md != nil {
+			*md = ResponseMetadata{}
+		}
+		retry, err := c.do(ctx, method, url, payload, header, result)
+		var rl *RateLimitError
+		if errors.As(err, &rl) {
+			c.stats.rateLimitHits.Add(1)
+		}
+		if err == nil || !retry || attempt >= maxRetries {
+			return attempt + 1, err
+		}
+		delay := c.backoff.delay(attempt)
+		if rl != nil && rl.RetryAfter > 0 {
+			delay = rl.RetryAfter
+		}
+		if c.retryBudget > 0 && time.Since(start)+delay >= c.retryBudget {
+			return attempt + 1, err
+		}
+		if waitErr := sleepContext(ctx, delay); waitErr != nil {
+			return attempt + 1, err
+		}
+	}
+}
+
+// do performs a single HTTP round trip, adding header to the common
+// headers. The returned bool reports whether the failure is transient and
+// the request may safely be retried.
+func (c *Client) do(ctx context.Context, method, url string, payload []byte, header http.Header, result interface{}) (bool, error) {
+	parent := ctx
+	timeout := c.timeoutFor(strings.TrimPrefix(url, c.baseURL+"/"))
+	if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Every attempt the breaker allows must end by recording its outcome
+	// or releasing it, or a half-open breaker would wait for its probe
+	// until the next cooldown
+	if err := c.breaker.allow(); err != nil {
+		return false, err
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	// Track whether any of the request went out, to tell failures that
+	// the server can't have acted on from ones it may have
+	var written atomic.Bool
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) { written.Store(true) },
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), method, url, reqBody)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		c.breaker.release()
+		return false, fmt.Errorf("failed to create request: %w", err)
 	}
-	
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-SDK-Version", SDKVersion)
-	req.Header.Set("X-SDK-Language", "go")
-	
-	resp, err := c.httpClient.Do(req)
+
+	c.setHeaders(req)
+	if err := c.authorize(ctx, req); err != nil {
+		c.breaker.release()
+		return false, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if c.compress {
+		// Setting Accept-Encoding ourselves turns off the transport's own
+		// decompression, so decompressBody must handle it
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	var key string
+	var cached *CacheEntry
+	if c.cache != nil && method == http.MethodGet {
+		key = cacheKey(req, header)
+		if entry, ok := c.cache.Get(ctx, key); ok {
+			cached = entry
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+	}
+
+	resp, err := c.send(c.httpClient, req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		if parent.Err() == nil {
+			c.breaker.record(true)
+		} else {
+			c.breaker.release()
+		}
+		// A request that was never written, e.g. because dialing failed, is
+		// safe to retry for any method. Once written, the server may have
+		// acted on it even though no response arrived, such as when the
+		// attempt timed out, so only idempotent requests are replayed.
+		retry := parent.Err() == nil && (!written.Load() || isIdempotent(req))
+		return retry, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
-	
+	c.breaker.record(resp.StatusCode >= 500)
+
+	if err := decompressBody(resp); err != nil {
+		return false, fmt.Errorf("failed to decompress response: %w", err)
+	}
+
+	if md := responseMetadataFrom(ctx); md != nil {
+		md.RequestID = resp.Header.Get(requestIDHeader)
+		md.StatusCode = resp.StatusCode
+		md.ETag = resp.Header.Get("ETag")
+		md.Header = resp.Header
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The credential may have been revoked; fetch a fresh one next time
+		if invalidator, ok := c.credentials.(interface{ Invalidate() }); ok {
+			invalidator.Invalidate()
+		}
+	}
+
 	// Handle error responses
 	if resp.StatusCode >= 400 {
-		return c.handleErrorResponse(resp)
+		return isRetryableStatus(resp.StatusCode) && isIdempotent(req), c.handleErrorResponse(resp)
 	}
-	
-	// Decode response if result interface provided
-	if result != nil && resp.ContentLength != 0 {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+
+	var body io.Reader = resp.Body
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		body = bytes.NewReader(cached.Body)
+	case key != "" && resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return false, fmt.Errorf("failed to read response: %w", err)
 		}
+		c.cache.Set(ctx, key, &CacheEntry{ETag: resp.Header.Get("ETag"), Body: data})
+		body = bytes.NewReader(data)
 	}
-	
+
+	// Decode response if result interface provided. ContentLength is -1 for
+	// chunked or compressed bodies, so an empty body is detected by the
+	// decoder hitting EOF before any value instead.
+	if result != nil && resp.StatusCode != http.StatusNoContent {
+		if err := c.newDecoder(body).Decode(result); err != nil && err != io.EOF {
+			return false, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// setHeaders sets the headers common to every API request
+func (c *Client) setHeaders(req *http.Request) {
+	c.mu.Lock()
+	apiKey := c.apiKey
+	c.mu.Unlock()
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	req.Header.Set("X-SDK-Version", SDKVersion)
+	req.Header.Set("X-SDK-Language", "go")
+	req.Header.Set("User-Agent", c.userAgent)
+}
+
+// authorize sets the Authorization header, replacing the API key with the
+// key attached to ctx by WithAPIKey, or else with the credential of the
+// configured provider, such as an OAuth2 access token
+func (c *Client) authorize(ctx context.Context, req *http.Request) error {
+	if key := apiKeyFrom(ctx); key != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+		return nil
+	}
+	if c.credentials == nil {
+		return nil
+	}
+	token, err := c.credentials.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	return nil
 }
 
+// withQuery appends the encoded query to endpoint, if there is one
+func withQuery(endpoint string, query url.Values) string {
+	if len(query) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + query.Encode()
+}
+
 func (c *Client) handleErrorResponse(resp *http.Response) error {
 	var errorResp struct {
-		Message string `json:"message"`
-		Code    string `json:"code"`
+		Message string                     `json:"message"`
+		Code    string                     `json:"code"`
+		Errors  map[string]json.RawMessage `json:"errors"`
 	}
 	
-	if err := json.NewDecoder(resp.Body).Decode(&errorResp); err != nil {
-		return fmt.Errorf("API error: %s", resp.Status)
+	// Fall back to the status line when the body isn't a JSON error so the
+	// caller still gets a typed error carrying the request ID
+	if err := json.NewDecoder(resp.Body).Decode(&errorResp); err != nil || errorResp.Message == "" {
+		errorResp.Message = resp.Status
 	}
-	
+	requestID := resp.Header.Get(requestIDHeader)
+
 	switch resp.StatusCode {
+	case 400, 422:
+		return &ValidationError{
+			StatusCode: resp.StatusCode,
+			Message:    errorResp.Message,
+			Fields:     fieldMessages(errorResp.Errors),
+			RequestID:  requestID,
+		}
 	case 401:
-		return &AuthenticationError{Message: errorResp.Message}
+		return &AuthenticationError{Message: errorResp.Message, RequestID: requestID}
 	case 404:
-		return &NotFoundError{Message: errorResp.Message}
+		return &NotFoundError{Message: errorResp.Message, RequestID: requestID}
+	case 412:
+		return &ConflictError{Message: errorResp.Message, RequestID: requestID}
 	case 429:
-		return &RateLimitError{Message: errorResp.Message}
+		return &RateLimitError{
+			Message:    errorResp.Message,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			RequestID:  requestID,
+		}
 	default:
+		if resp.StatusCode >= 500 {
+			return &ServerError{
+				StatusCode: resp.StatusCode,
+				Message:    errorResp.Message,
+				Code:       errorResp.Code,
+				RequestID:  requestID,
+			}
+		}
 		return &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    errorResp.Message,
 			Code:       errorResp.Code,
+			RequestID:  requestID,
+		}
+	}
+}
+
+// fieldMessages flattens the per-field errors of a validation response,
+// where each field maps to a message or a list of messages
+func fieldMessages(errs map[string]json.RawMessage) map[string]string {
+	if len(errs) == 0 {
+		return nil
+	}
+	fields := make(map[string]string, len(errs))
+	for field, raw := range errs {
+		var msg string
+		if err := json.Unmarshal(raw, &msg); err == nil {
+			fields[field] = msg
+			continue
 		}
+		var msgs []string
+		if err := json.Unmarshal(raw, &msgs); err == nil {
+			fields[field] = strings.Join(msgs, "; ")
+			continue
+		}
+		fields[field] = string(raw)
 	}
+	return fields
 }
 
 // AgentService handles agent-related operations
@@ -177,11 +850,60 @@ type AgentService struct {
 
 // Create creates a new agent
 func (s *AgentService) Create(ctx context.Context, req *CreateAgentRequest) (*Agent, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.client.validateConfigs {
+		if err := s.ValidateConfig(ctx, req.Type, req.Config); err != nil {
+			return nil, err
+		}
+	}
 	var agent Agent
 	err := s.client.request(ctx, http.MethodPost, "agents", req, &agent)
 	return &agent, err
 }
 
+// BatchCreate creates several agents in one round trip. The returned
+// results are in the same order as reqs; each holds either the created
+// agent or the error for that item, so partial failures can be reported.
+func (s *AgentService) BatchCreate(ctx context.Context, reqs []*CreateAgentRequest) ([]*BatchCreateResult, error) {
+	var resp struct {
+		Results []struct {
+			Index int    `json:"index"`
+			Agent *Agent `json:"agent"`
+			Error *struct {
+				Status  int    `json:"status"`
+				Message string `json:"message"`
+				Code    string `json:"code"`
+			} `json:"error"`
+		} `json:"results"`
+	}
+	for i, r := range reqs {
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("agent %d: %w", i, err)
+		}
+	}
+	req := map[string]interface{}{"agents": reqs}
+	if err := s.client.request(ctx, http.MethodPost, "agents/batch", req, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]*BatchCreateResult, len(resp.Results))
+	for i, r := range resp.Results {
+		result := &BatchCreateResult{Index: r.Index, Agent: r.Agent}
+		if r.Error != nil {
+			result.Agent = nil
+			result.Err = &APIError{
+				StatusCode: r.Error.Status,
+				Message:    r.Error.Message,
+				Code:       r.Error.Code,
+			}
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
 // Get retrieves an agent by ID
 func (s *AgentService) Get(ctx context.Context, agentID string) (*Agent, error) {
 	var agent Agent
@@ -189,61 +911,533 @@ func (s *AgentService) Get(ctx context.Context, agentID string) (*Agent, error)
 	return &agent, err
 }
 
-// List retrieves all agents
-func (s *AgentService) List(ctx context.Context, opts *ListAgentsOptions) ([]*Agent, error) {
-	var agents []*Agent
-	endpoint := "agents"
+// List retrieves a page of agents. Pass the returned NextCursor back in
+// opts.Cursor to fetch the following page.
+func (s *AgentService) List(ctx context.Context, opts *ListAgentsOptions) (*Page[*Agent], error) {
+	query := url.Values{}
 	if opts != nil {
-		// Add query parameters if needed
-		endpoint += fmt.Sprintf("?limit=%d", opts.Limit)
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
 		if opts.Status != "" {
-			endpoint += fmt.Sprintf("&status=%s", opts.Status)
+			query.Set("status", opts.Status)
 		}
 		if opts.Type != "" {
-			endpoint += fmt.Sprintf("&type=%s", opts.Type)
+			query.Set("type", opts.Type)
+		}
+		if opts.Cursor != "" {
+			query.Set("cursor", opts.Cursor)
 		}
 	}
-	err := s.client.request(ctx, http.MethodGet, endpoint, nil, &agents)
-	return agents, err
+	return getPage[*Agent](ctx, s.client, withQuery("agents", query), "agents")
+}
+
+// Search retrieves a page of agents matching the name and config criteria
+// in opts. Pass the returned NextCursor back in opts.Cursor to fetch the
+// following page.
+func (s *AgentService) Search(ctx context.Context, opts *SearchAgentsOptions) (*Page[*Agent], error) {
+	query := url.Values{}
+	if opts != nil {
+		if opts.Name != "" {
+			query.Set("name", opts.Name)
+		}
+		for path, value := range opts.Config {
+			query.Set("config."+path, value)
+		}
+		if opts.Status != "" {
+			query.Set("status", opts.Status)
+		}
+		if opts.Type != "" {
+			query.Set("type", opts.Type)
+		}
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Cursor != "" {
+			query.Set("cursor", opts.Cursor)
+		}
+	}
+	return getPage[*Agent](ctx, s.client, withQuery("agents/search", query), "agents")
+}
+
+// ListAll walks every page of agents matching opts, calling fn for each
+// agent in order. It stops at the first error returned by fn, by a page
+// request, or by ctx.
+func (s *AgentService) ListAll(ctx context.Context, opts *ListAgentsOptions, fn func(*Agent) error) error {
+	pageOpts := ListAgentsOptions{}
+	if opts != nil {
+		pageOpts = *opts
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := s.List(ctx, &pageOpts)
+		if err != nil {
+			return err
+		}
+		for _, agent := range page.Items {
+			if err := fn(agent); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		pageOpts.Cursor = page.NextCursor
+	}
 }
 
-// Update updates an agent
+// Update updates an agent. If req.IfMatch is set the update only applies
+// if the agent is unchanged since it was read, and fails with a
+// *ConflictError otherwise.
 func (s *AgentService) Update(ctx context.Context, agentID string, req *UpdateAgentRequest) (*Agent, error) {
 	var agent Agent
+	if req != nil && req.IfMatch != "" {
+		ctx = WithHeader(ctx, "If-Match", req.IfMatch)
+	}
 	err := s.client.request(ctx, http.MethodPatch, fmt.Sprintf("agents/%s", agentID), req, &agent)
 	return &agent, err
 }
 
-// Delete deletes an agent
-func (s *AgentService) Delete(ctx context.Context, agentID string) error {
-	return s.client.request(ctx, http.MethodDelete, fmt.Sprintf("agents/%s", agentID), nil, nil)
+// Clone creates a new agent named newName with the configuration and
+// policies of an existing agent. Runtime state is not copied; the clone
+// starts in the default status.
+func (s *AgentService) Clone(ctx context.Context, agentID, newName string) (*Agent, error) {
+	if newName == "" {
+		return nil, &ValidationError{
+			Message: "invalid clone request",
+			Fields:  map[string]string{"name": "is required"},
+		}
+	}
+	var agent Agent
+	req := map[string]string{"name": newName}
+	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("agents/%s/clone", agentID), req, &agent)
+	return &agent, err
 }
 
-// WorkflowService handles workflow-related operations
-type WorkflowService struct {
-	client *Client
+// Start starts an agent. Starting an agent that is already active returns
+// its current state.
+func (s *AgentService) Start(ctx context.Context, agentID string) (*Agent, error) {
+	return s.transition(ctx, agentID, "start", "active")
 }
 
-// Create creates a new workflow
-func (s *WorkflowService) Create(ctx context.Context, req *CreateWorkflowRequest) (*Workflow, error) {
-	var workflow Workflow
-	err := s.client.request(ctx, http.MethodPost, "workflows", req, &workflow)
-	return &workflow, err
+// Stop stops an agent. Stopping an agent that is already stopped returns
+// its current state.
+func (s *AgentService) Stop(ctx context.Context, agentID string) (*Agent, error) {
+	return s.transition(ctx, agentID, "stop", "stopped")
 }
 
-// Execute executes a workflow
-func (s *WorkflowService) Execute(ctx context.Context, workflowID string, input map[string]interface{}) (*WorkflowResult, error) {
-	var result WorkflowResult
-	req := map[string]interface{}{"input": input}
-	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("workflows/%s/execute", workflowID), req, &result)
-	return &result, err
+// Restart stops and starts an agent again
+func (s *AgentService) Restart(ctx context.Context, agentID string) (*Agent, error) {
+	return s.transition(ctx, agentID, "restart", "")
 }
 
-// GetHistory retrieves workflow execution history
-func (s *WorkflowService) GetHistory(ctx context.Context, workflowID string, limit int) ([]*WorkflowExecution, error) {
-	var executions []*WorkflowExecution
-	endpoint := fmt.Sprintf("workflows/%s/history?limit=%d", workflowID, limit)
-	err := s.client.request(ctx, http.MethodGet, endpoint, nil, &executions)
+// transition posts a lifecycle action for an agent. The server rejects
+// transitions that aren't valid from the agent's current status with a
+// conflict; if the agent is already in the target status that is treated
+// as success, otherwise the error names the status that blocked it.
+func (s *AgentService) transition(ctx context.Context, agentID, action, target string) (*Agent, error) {
+	var agent Agent
+	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("agents/%s/%s", agentID, action), nil, &agent)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		current, getErr := s.Get(ctx, agentID)
+		if getErr != nil {
+			return nil, err
+		}
+		if target != "" && current.Status == target {
+			return current, nil
+		}
+		return nil, fmt.Errorf("cannot %s agent %s in status %q: %w", action, agentID, current.Status, err)
+	}
+	return &agent, err
+}
+
+// WaitForHealthy polls an agent's health until it reports healthy, or its
+// health score reaches opts.MinScore when set, and returns the final
+// metrics. If opts.MaxAttempts polls pass without that, the last metrics
+// are returned with an error matching ErrNotHealthy. When ctx is done it
+// returns ctx.Err() at once, even mid-wait.
+func (s *AgentService) WaitForHealthy(ctx context.Context, agentID string, opts *WaitForHealthyOptions) (*HealthMetrics, error) {
+	o := WaitForHealthyOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultPollInterval
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		metrics, err := s.client.Telemetry.GetHealth(ctx, agentID)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, err
+		}
+		if metrics.Status == HealthStatusHealthy || (o.MinScore > 0 && metrics.HealthScore >= o.MinScore) {
+			return metrics, nil
+		}
+		if o.MaxAttempts > 0 && attempt >= o.MaxAttempts {
+			return metrics, fmt.Errorf("agent %s still %q with score %d after %d attempts: %w",
+				agentID, metrics.Status, metrics.HealthScore, attempt, ErrNotHealthy)
+		}
+		if err := sleepContext(ctx, o.Interval); err != nil {
+			return metrics, err
+		}
+	}
+}
+
+// GetLogs retrieves a page of an agent's runtime log lines, oldest first.
+// Set opts.Tail to fetch only the most recent lines. Pass the returned
+// NextCursor back in opts.Cursor to fetch the following page.
+func (s *AgentService) GetLogs(ctx context.Context, agentID string, opts *LogOptions) (*Page[*LogEntry], error) {
+	query := url.Values{}
+	if opts != nil {
+		if !opts.Start.IsZero() {
+			query.Set("start_date", opts.Start.Format(time.RFC3339))
+		}
+		if !opts.End.IsZero() {
+			query.Set("end_date", opts.End.Format(time.RFC3339))
+		}
+		if opts.Level != "" {
+			query.Set("level", opts.Level)
+		}
+		if opts.Stream != "" {
+			query.Set("stream", opts.Stream)
+		}
+		if opts.Tail > 0 {
+			query.Set("tail", strconv.Itoa(opts.Tail))
+		}
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Cursor != "" {
+			query.Set("cursor", opts.Cursor)
+		}
+	}
+	endpoint := withQuery(fmt.Sprintf("agents/%s/logs", agentID), query)
+	return getPage[*LogEntry](ctx, s.client, endpoint, "logs")
+}
+
+// GetMetrics retrieves an agent's health together with its error rate and
+// resource usage over a recent window, fetching the health and the
+// telemetry rollups it is computed from concurrently. It fails if any of
+// them can't be fetched.
+func (s *AgentService) GetMetrics(ctx context.Context, agentID string, opts *AgentMetricsOptions) (*AgentMetrics, error) {
+	window := DefaultMetricsWindow
+	if opts != nil && opts.Window > 0 {
+		window = opts.Window
+	}
+	end := time.Now().UTC()
+	metrics := &AgentMetrics{AgentID: agentID, Start: end.Add(-window), End: end}
+
+	telemetry := s.client.Telemetry
+	rollup := func(eventType, metric, function string) func() ([]AggregatePoint, error) {
+		return func() ([]AggregatePoint, error) {
+			return telemetry.Aggregate(ctx, agentID, &AggregateOptions{
+				Metric:    metric,
+				Function:  function,
+				Start:     metrics.Start,
+				End:       metrics.End,
+				EventType: eventType,
+			})
+		}
+	}
+	rollups := []func() ([]AggregatePoint, error){
+		rollup(EventTypeInference, "latency_ms", AggregateAvg),
+		// Every error payload has a code, so counting codes counts errors
+		rollup(EventTypeError, "code", AggregateCount),
+		rollup(EventTypeInference, "prompt_tokens", AggregateSum),
+		rollup(EventTypeInference, "completion_tokens", AggregateSum),
+		rollup(EventTypeInference, "cost", AggregateSum),
+	}
+
+	// Index 0 fetches the health, the others the rollups in order
+	points := make([][]AggregatePoint, len(rollups))
+	errs := make([]error, len(rollups)+1)
+	fanOut(ctx, len(errs), len(errs), func(i int) {
+		if i == 0 {
+			metrics.Health, errs[i] = telemetry.GetHealth(ctx, agentID)
+			return
+		}
+		points[i-1], errs[i] = rollups[i-1]()
+	}, func(i int, err error) {
+		errs[i] = err
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	latency, inferences := sumPoints(points[0], true)
+	_, metrics.Errors = sumPoints(points[1], false)
+	promptTokens, _ := sumPoints(points[2], false)
+	completionTokens, _ := sumPoints(points[3], false)
+	metrics.Inferences = inferences
+	metrics.AvgLatencyMs = latency
+	metrics.Tokens = int(promptTokens + completionTokens)
+	metrics.Cost, _ = sumPoints(points[4], false)
+	if total := metrics.Inferences + metrics.Errors; total > 0 {
+		metrics.ErrorRate = float64(metrics.Errors) / float64(total)
+	}
+	return metrics, nil
+}
+
+// sumPoints totals the values and event counts of rollup points. With
+// average set the values are averages, and their mean weighted by count is
+// returned instead of their sum.
+func sumPoints(points []AggregatePoint, average bool) (float64, int) {
+	var value float64
+	var count int
+	for _, p := range points {
+		if average {
+			value += p.Value * float64(p.Count)
+		} else {
+			value += p.Value
+		}
+		count += p.Count
+	}
+	if average && count > 0 {
+		value /= float64(count)
+	}
+	return value, count
+}
+
+// Delete deletes an agent
+func (s *AgentService) Delete(ctx context.Context, agentID string) error {
+	return s.client.request(ctx, http.MethodDelete, fmt.Sprintf("agents/%s", agentID), nil, nil)
+}
+
+// Purge deletes an agent together with the resources attached to it: it
+// deregisters the agent from federation, detaches its policies and deletes
+// its workflows, then deletes the agent itself. Resources that are already
+// gone are skipped, so a failed purge can simply be retried. If any
+// attached resource can't be removed, the agent is kept, so it can still
+// be found to retry, and the errors are returned joined.
+func (s *AgentService) Purge(ctx context.Context, agentID string) error {
+	if err := s.client.Federation.Deregister(ctx, agentID); err != nil {
+		return fmt.Errorf("failed to deregister agent from federation: %w", err)
+	}
+
+	policies, err := s.client.Policies.List(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to list policies: %w", err)
+	}
+	// Collect every workflow before deleting any, so deletions don't shift
+	// the pages being walked
+	var workflowIDs []string
+	opts := &ListWorkflowsOptions{AgentID: agentID}
+	for {
+		page, err := s.client.Workflows.List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list workflows: %w", err)
+		}
+		for _, workflow := range page.Items {
+			workflowIDs = append(workflowIDs, workflow.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	var errs []error
+	for _, policy := range policies {
+		if err := s.client.Policies.Remove(ctx, agentID, policy.ID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove policy %s: %w", policy.ID, err))
+		}
+	}
+	for _, workflowID := range workflowIDs {
+		err := s.client.Workflows.Delete(ctx, workflowID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			errs = append(errs, fmt.Errorf("failed to delete workflow %s: %w", workflowID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return s.Delete(ctx, agentID)
+}
+
+// WorkflowService handles workflow-related operations
+type WorkflowService struct {
+	client *Client
+}
+
+// Create creates a new workflow
+func (s *WorkflowService) Create(ctx context.Context, req *CreateWorkflowRequest) (*Workflow, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	var workflow Workflow
+	err := s.client.request(ctx, http.MethodPost, "workflows", req, &workflow)
+	return &workflow, err
+}
+
+// Validate checks a workflow definition server-side without creating a
+// workflow. Problems with the definition are reported in the result, not
+// as an error.
+func (s *WorkflowService) Validate(ctx context.Context, definition map[string]interface{}) (*WorkflowValidation, error) {
+	var result WorkflowValidation
+	req := map[string]interface{}{"definition": definition}
+	err := s.client.request(ctx, http.MethodPost, "workflows/validate", req, &result)
+	return &result, err
+}
+
+// Get retrieves a workflow by ID
+func (s *WorkflowService) Get(ctx context.Context, workflowID string) (*Workflow, error) {
+	var workflow Workflow
+	err := s.client.request(ctx, http.MethodGet, fmt.Sprintf("workflows/%s", workflowID), nil, &workflow)
+	return &workflow, err
+}
+
+// List retrieves a page of workflows. Pass the returned NextCursor back in
+// opts.Cursor to fetch the following page.
+func (s *WorkflowService) List(ctx context.Context, opts *ListWorkflowsOptions) (*Page[*Workflow], error) {
+	query := url.Values{}
+	if opts != nil {
+		if opts.AgentID != "" {
+			query.Set("agent_id", opts.AgentID)
+		}
+		if opts.Status != "" {
+			query.Set("status", string(opts.Status))
+		}
+		if opts.Sort != "" {
+			query.Set("sort", string(opts.Sort))
+			if opts.Descending {
+				query.Set("order", "desc")
+			}
+		}
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Cursor != "" {
+			query.Set("cursor", opts.Cursor)
+		}
+	}
+	return getPage[*Workflow](ctx, s.client, withQuery("workflows", query), "workflows")
+}
+
+// Delete deletes a workflow
+func (s *WorkflowService) Delete(ctx context.Context, workflowID string) error {
+	return s.client.request(ctx, http.MethodDelete, fmt.Sprintf("workflows/%s", workflowID), nil, nil)
+}
+
+// Execute executes a workflow
+func (s *WorkflowService) Execute(ctx context.Context, workflowID string, input map[string]interface{}) (*WorkflowResult, error) {
+	var result WorkflowResult
+	req := map[string]interface{}{"input": input}
+	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("workflows/%s/execute", workflowID), req, &result)
+	return &result, err
+}
+
+// ExecuteAsync starts a workflow execution without waiting for it to
+// finish. Use GetExecution or WaitForCompletion to follow its progress.
+func (s *WorkflowService) ExecuteAsync(ctx context.Context, workflowID string, input map[string]interface{}) (*ExecutionHandle, error) {
+	var handle ExecutionHandle
+	req := map[string]interface{}{"input": input}
+	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("workflows/%s/execute?async=true", workflowID), req, &handle)
+	return &handle, err
+}
+
+// GetExecution retrieves the current state of a workflow execution
+func (s *WorkflowService) GetExecution(ctx context.Context, executionID string) (*WorkflowResult, error) {
+	var result WorkflowResult
+	err := s.client.request(ctx, http.MethodGet, fmt.Sprintf("executions/%s", executionID), nil, &result)
+	return &result, err
+}
+
+// WaitForCompletion polls an execution every interval until it reaches a
+// terminal status and returns the final result. A non-positive interval
+// uses DefaultPollInterval. When ctx is done it returns ctx.Err() at once,
+// even mid-wait.
+func (s *WorkflowService) WaitForCompletion(ctx context.Context, executionID string, interval time.Duration) (*WorkflowResult, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := s.GetExecution(ctx, executionID)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, err
+		}
+		if result.Status.IsTerminal() {
+			return result, nil
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// StreamOutput returns the output of a finished execution as a stream,
+// for outputs too large to load into memory as WorkflowResult.Output. The
+// caller must close it; ctx must stay alive until reading is done. The
+// request is not retried.
+//
+//	output, err := client.Workflows.StreamOutput(ctx, executionID)
+//	if err != nil {
+//		return err
+//	}
+//	defer output.Close()
+//	_, err = io.Copy(file, output)
+func (s *WorkflowService) StreamOutput(ctx context.Context, executionID string) (io.ReadCloser, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/octet-stream")
+	resp, _, err := s.client.openStream(ctx, fmt.Sprintf("executions/%s/output", executionID), header)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Cancel requests cancellation of a running execution and returns its
+// updated state. If the execution has already finished, its terminal state
+// is returned instead of an error.
+func (s *WorkflowService) Cancel(ctx context.Context, workflowID, executionID string) (*WorkflowResult, error) {
+	var result WorkflowResult
+	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("workflows/%s/executions/%s/cancel", workflowID, executionID), nil, &result)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		// The execution can no longer be cancelled; report where it ended up
+		current, getErr := s.GetExecution(ctx, executionID)
+		if getErr == nil && current.Status.IsTerminal() {
+			return current, nil
+		}
+	}
+	return &result, err
+}
+
+// GetExecutionLogs retrieves the per-step record of a workflow execution:
+// each step's status, input, output and, for a failed step, its error, to
+// find where and why a run went wrong
+func (s *WorkflowService) GetExecutionLogs(ctx context.Context, workflowID, executionID string) (*ExecutionLogs, error) {
+	var logs ExecutionLogs
+	err := s.client.request(ctx, http.MethodGet, fmt.Sprintf("workflows/%s/executions/%s/logs", workflowID, executionID), nil, &logs)
+	return &logs, err
+}
+
+// GetHistory retrieves workflow execution history
+func (s *WorkflowService) GetHistory(ctx context.Context, workflowID string, limit int) ([]*WorkflowExecution, error) {
+	var executions []*WorkflowExecution
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	endpoint := withQuery(fmt.Sprintf("workflows/%s/history", workflowID), query)
+	err := s.client.request(ctx, http.MethodGet, endpoint, nil, &executions)
 	return executions, err
 }
 
@@ -254,11 +1448,36 @@ type PolicyService struct {
 
 // Apply applies a governance policy to an agent
 func (s *PolicyService) Apply(ctx context.Context, agentID string, req *ApplyPolicyRequest) (*Policy, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	var policy Policy
 	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("agents/%s/policies", agentID), req, &policy)
 	return &policy, err
 }
 
+// ValidateRules checks the rules of a policy for framework server-side
+// without applying it. Problems with the rules are reported in the result,
+// not as an error. LintPolicyRules performs the common checks locally.
+func (s *PolicyService) ValidateRules(ctx context.Context, framework string, rules map[string]interface{}) (*PolicyRulesValidation, error) {
+	var result PolicyRulesValidation
+	req := map[string]interface{}{"framework": framework, "rules": rules}
+	err := s.client.request(ctx, http.MethodPost, "policies/validate", req, &result)
+	return &result, err
+}
+
+// Simulate evaluates a policy against an agent without applying it and
+// returns the violations it would report, so its impact can be previewed
+// before enforcement
+func (s *PolicyService) Simulate(ctx context.Context, agentID string, req *ApplyPolicyRequest) (*ComplianceReport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	var report ComplianceReport
+	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("agents/%s/policies/simulate", agentID), req, &report)
+	return &report, err
+}
+
 // List retrieves policies for an agent
 func (s *PolicyService) List(ctx context.Context, agentID string) ([]*Policy, error) {
 	var policies []*Policy
@@ -266,6 +1485,31 @@ func (s *PolicyService) List(ctx context.Context, agentID string) ([]*Policy, er
 	return policies, err
 }
 
+// Get retrieves a single policy applied to an agent
+func (s *PolicyService) Get(ctx context.Context, agentID, policyID string) (*Policy, error) {
+	var policy Policy
+	err := s.client.request(ctx, http.MethodGet, fmt.Sprintf("agents/%s/policies/%s", agentID, policyID), nil, &policy)
+	return &policy, err
+}
+
+// Update modifies a policy applied to an agent in place. Only the fields
+// set in req are changed.
+func (s *PolicyService) Update(ctx context.Context, agentID, policyID string, req *UpdatePolicyRequest) (*Policy, error) {
+	var policy Policy
+	err := s.client.request(ctx, http.MethodPatch, fmt.Sprintf("agents/%s/policies/%s", agentID, policyID), req, &policy)
+	return &policy, err
+}
+
+// Remove detaches a policy from an agent. Removing a policy that isn't
+// applied to the agent is not an error.
+func (s *PolicyService) Remove(ctx context.Context, agentID, policyID string) error {
+	err := s.client.request(ctx, http.MethodDelete, fmt.Sprintf("agents/%s/policies/%s", agentID, policyID), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
 // CheckCompliance checks policy compliance for an agent
 func (s *PolicyService) CheckCompliance(ctx context.Context, agentID string) (*ComplianceReport, error) {
 	var report ComplianceReport
@@ -273,29 +1517,364 @@ func (s *PolicyService) CheckCompliance(ctx context.Context, agentID string) (*C
 	return &report, err
 }
 
+// ListComplianceHistory retrieves a page of an agent's past compliance
+// reports, newest first, e.g. to chart compliance drift or as audit
+// evidence. Pass the returned NextCursor back in opts.Cursor to fetch the
+// following page.
+func (s *PolicyService) ListComplianceHistory(ctx context.Context, agentID string, opts *ComplianceHistoryOptions) (*Page[*ComplianceReport], error) {
+	query := url.Values{}
+	if opts != nil {
+		if !opts.Start.IsZero() {
+			query.Set("start_date", opts.Start.Format(time.RFC3339))
+		}
+		if !opts.End.IsZero() {
+			query.Set("end_date", opts.End.Format(time.RFC3339))
+		}
+		if opts.Severity != "" {
+			query.Set("severity", opts.Severity)
+		}
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Cursor != "" {
+			query.Set("cursor", opts.Cursor)
+		}
+	}
+	endpoint := withQuery(fmt.Sprintf("agents/%s/compliance/history", agentID), query)
+	return getPage[*ComplianceReport](ctx, s.client, endpoint, "reports")
+}
+
+// CheckComplianceBatch checks compliance for many agents concurrently,
+// with at most concurrency checks in flight (a default limit if zero).
+// The result for each agent holds its report or the error checking it;
+// agents not checked because ctx was cancelled report the context error.
+func (s *PolicyService) CheckComplianceBatch(ctx context.Context, agentIDs []string, concurrency int) map[string]*ComplianceResult {
+	var mu sync.Mutex
+	results := make(map[string]*ComplianceResult, len(agentIDs))
+	set := func(i int, result *ComplianceResult) {
+		mu.Lock()
+		results[agentIDs[i]] = result
+		mu.Unlock()
+	}
+
+	fanOut(ctx, len(agentIDs), concurrency, func(i int) {
+		report, err := s.CheckCompliance(ctx, agentIDs[i])
+		if err != nil {
+			report = nil
+		}
+		set(i, &ComplianceResult{Report: report, Err: err})
+	}, func(i int, err error) {
+		set(i, &ComplianceResult{Err: err})
+	})
+	return results
+}
+
+// ApplyBatch applies the same policy to many agents concurrently, with at
+// most concurrency requests in flight (a default limit if zero). req is
+// validated once up front; after that the result for each agent holds its
+// applied policy or the error applying it, so a partial rollout can be
+// retried for just the agents that failed. Agents not reached because ctx
+// was cancelled report the context error.
+func (s *PolicyService) ApplyBatch(ctx context.Context, agentIDs []string, req *ApplyPolicyRequest, concurrency int) (map[string]*ApplyPolicyResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	results := make(map[string]*ApplyPolicyResult, len(agentIDs))
+	set := func(i int, result *ApplyPolicyResult) {
+		mu.Lock()
+		results[agentIDs[i]] = result
+		mu.Unlock()
+	}
+
+	fanOut(ctx, len(agentIDs), concurrency, func(i int) {
+		policy, err := s.Apply(ctx, agentIDs[i], req)
+		if err != nil {
+			policy = nil
+		}
+		set(i, &ApplyPolicyResult{Policy: policy, Err: err})
+	}, func(i int, err error) {
+		set(i, &ApplyPolicyResult{Err: err})
+	})
+	return results, nil
+}
+
 // TelemetryService handles telemetry-related operations
 type TelemetryService struct {
-	client *Client
+	client          *Client
+	ingestBatchSize int
 }
 
-// Get retrieves telemetry events
-func (s *TelemetryService) Get(ctx context.Context, agentID string, opts *TelemetryOptions) ([]*TelemetryEvent, error) {
-	var events []*TelemetryEvent
-	endpoint := fmt.Sprintf("agents/%s/telemetry", agentID)
+// Get retrieves a page of telemetry events. Pass the returned NextCursor
+// back in opts.Cursor to fetch the following page.
+func (s *TelemetryService) Get(ctx context.Context, agentID string, opts *TelemetryOptions) (*Page[*TelemetryEvent], error) {
+	query := url.Values{}
 	if opts != nil {
-		endpoint += "?"
-		if opts.StartDate != "" {
-			endpoint += fmt.Sprintf("start_date=%s&", opts.StartDate)
+		if start := formatTelemetryDate(opts.Start, opts.StartDate); start != "" {
+			query.Set("start_date", start)
 		}
-		if opts.EndDate != "" {
-			endpoint += fmt.Sprintf("end_date=%s&", opts.EndDate)
+		if end := formatTelemetryDate(opts.End, opts.EndDate); end != "" {
+			query.Set("end_date", end)
 		}
 		if opts.EventType != "" {
-			endpoint += fmt.Sprintf("event_type=%s", opts.EventType)
+			query.Set("event_type", opts.EventType)
+		}
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Cursor != "" {
+			query.Set("cursor", opts.Cursor)
+		}
+	}
+	endpoint := withQuery(fmt.Sprintf("agents/%s/telemetry", agentID), query)
+	return getPage[*TelemetryEvent](ctx, s.client, endpoint, "events")
+}
+
+// GetAll walks every page of telemetry events matching opts, calling fn
+// for each event in order. If chunk is positive, the query window must be
+// set with Start and End or StartDate and EndDate. It is then split into
+// consecutive sub-windows of at most chunk that are queried one after
+// another, keeping each query small. It stops at the first error returned
+// by fn, by a request, or by ctx.
+func (s *TelemetryService) GetAll(ctx context.Context, agentID string, opts *TelemetryOptions, chunk time.Duration, fn func(*TelemetryEvent) error) error {
+	base := TelemetryOptions{}
+	if opts != nil {
+		base = *opts
+	}
+	base.Cursor = ""
+
+	if chunk <= 0 {
+		return s.walk(ctx, agentID, base, fn)
+	}
+
+	start, err := parseTelemetryDate(base.Start, base.StartDate)
+	if err != nil {
+		return &ValidationError{Message: "chunking requires a valid start date", Fields: map[string]string{"start_date": err.Error()}}
+	}
+	end, err := parseTelemetryDate(base.End, base.EndDate)
+	if err != nil {
+		return &ValidationError{Message: "chunking requires a valid end date", Fields: map[string]string{"end_date": err.Error()}}
+	}
+	for from := start; from.Before(end); from = from.Add(chunk) {
+		to := from.Add(chunk)
+		if to.After(end) {
+			to = end
+		}
+		window := base
+		window.Start, window.End = from, to
+		if err := s.walk(ctx, agentID, window, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk calls fn for every event on every page of the query opts
+func (s *TelemetryService) walk(ctx context.Context, agentID string, opts TelemetryOptions, fn func(*TelemetryEvent) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := s.Get(ctx, agentID, &opts)
+		if err != nil {
+			return err
 		}
+		for _, event := range page.Items {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
+
+// Delete permanently deletes an agent's telemetry events in the window
+// given by opts and returns how many were deleted. Without Start and End
+// every matching event is deleted. opts.Confirm must be set, as a guard
+// against accidental mass deletion.
+func (s *TelemetryService) Delete(ctx context.Context, agentID string, opts *DeleteTelemetryOptions) (int, error) {
+	if opts == nil || !opts.Confirm {
+		return 0, &ValidationError{
+			Message: "telemetry deletion is destructive and must be confirmed",
+			Fields:  map[string]string{"confirm": "must be true"},
+		}
+	}
+	query := url.Values{}
+	if !opts.Start.IsZero() {
+		query.Set("start_date", opts.Start.Format(time.RFC3339))
+	}
+	if !opts.End.IsZero() {
+		query.Set("end_date", opts.End.Format(time.RFC3339))
+	}
+	if opts.EventType != "" {
+		query.Set("event_type", opts.EventType)
+	}
+
+	var resp struct {
+		Deleted int `json:"deleted"`
 	}
-	err := s.client.request(ctx, http.MethodGet, endpoint, nil, &events)
-	return events, err
+	endpoint := withQuery(fmt.Sprintf("agents/%s/telemetry", agentID), query)
+	err := s.client.request(ctx, http.MethodDelete, endpoint, nil, &resp)
+	return resp.Deleted, err
+}
+
+// Aggregate computes a rollup of an agent's telemetry server-side,
+// returning one point per opts.Interval bucket in time order. Use it in
+// place of downloading raw events to compute sums, averages or
+// percentiles.
+func (s *TelemetryService) Aggregate(ctx context.Context, agentID string, opts *AggregateOptions) ([]AggregatePoint, error) {
+	fields := map[string]string{}
+	if opts == nil || opts.Metric == "" {
+		fields["metric"] = "is required"
+	}
+	if opts == nil || opts.Function == "" {
+		fields["function"] = "is required"
+	}
+	if opts != nil && opts.Interval < 0 {
+		fields["interval"] = "must not be negative"
+	} else if opts != nil && opts.Interval > 0 && opts.Interval < time.Second {
+		// It is sent in whole seconds, so it would be dropped
+		fields["interval"] = "must be at least 1s"
+	}
+	if len(fields) > 0 {
+		return nil, &ValidationError{Message: "invalid aggregate options", Fields: fields}
+	}
+
+	query := url.Values{}
+	query.Set("metric", opts.Metric)
+	query.Set("function", opts.Function)
+	if opts.Interval > 0 {
+		query.Set("interval", strconv.FormatInt(int64(opts.Interval/time.Second), 10))
+	}
+	if !opts.Start.IsZero() {
+		query.Set("start_date", opts.Start.Format(time.RFC3339))
+	}
+	if !opts.End.IsZero() {
+		query.Set("end_date", opts.End.Format(time.RFC3339))
+	}
+	if opts.EventType != "" {
+		query.Set("event_type", opts.EventType)
+	}
+
+	var points []AggregatePoint
+	endpoint := withQuery(fmt.Sprintf("agents/%s/telemetry/aggregate", agentID), query)
+	err := s.client.request(ctx, http.MethodGet, endpoint, nil, &points)
+	return points, err
+}
+
+// GetHealthBatch retrieves health metrics for many agents concurrently,
+// with at most workers requests in flight (a default limit if zero), and
+// summarizes them. Agents whose metrics couldn't be fetched are reported
+// with their error and left out of the average score.
+func (s *TelemetryService) GetHealthBatch(ctx context.Context, agentIDs []string, workers int) *HealthBatch {
+	var mu sync.Mutex
+	batch := &HealthBatch{Results: make(map[string]*HealthResult, len(agentIDs))}
+	set := func(i int, result *HealthResult) {
+		mu.Lock()
+		batch.Results[agentIDs[i]] = result
+		mu.Unlock()
+	}
+
+	fanOut(ctx, len(agentIDs), workers, func(i int) {
+		metrics, err := s.GetHealth(ctx, agentIDs[i])
+		if err != nil {
+			metrics = nil
+		}
+		set(i, &HealthResult{Metrics: metrics, Err: err})
+	}, func(i int, err error) {
+		set(i, &HealthResult{Err: err})
+	})
+
+	var total int
+	for _, r := range batch.Results {
+		if r.Err != nil {
+			batch.Summary.Failed++
+			continue
+		}
+		batch.Summary.Checked++
+		total += r.Metrics.HealthScore
+		if r.Metrics.Status != HealthStatusHealthy {
+			batch.Summary.Unhealthy++
+		}
+	}
+	if batch.Summary.Checked > 0 {
+		batch.Summary.AverageScore = float64(total) / float64(batch.Summary.Checked)
+	}
+	return batch
+}
+
+// Ingest pushes telemetry events for an agent, splitting them into
+// requests of at most the configured ingest batch size. The results are in
+// the same order as events and report whether each was accepted. If a
+// request fails outright, the results for the batches already sent are
+// returned together with the error.
+func (s *TelemetryService) Ingest(ctx context.Context, agentID string, events []*TelemetryEvent) ([]*IngestResult, error) {
+	size := s.ingestBatchSize
+	if size <= 0 {
+		size = len(events)
+	}
+
+	results := make([]*IngestResult, 0, len(events))
+	for start := 0; start < len(events); start += size {
+		end := start + size
+		if end > len(events) {
+			end = len(events)
+		}
+
+		var resp struct {
+			Results []struct {
+				Index    int  `json:"index"`
+				Accepted bool `json:"accepted"`
+				Error    *struct {
+					Message string `json:"message"`
+					Code    string `json:"code"`
+				} `json:"error"`
+			} `json:"results"`
+		}
+		req := map[string]interface{}{"events": events[start:end]}
+		endpoint := fmt.Sprintf("agents/%s/telemetry/ingest", agentID)
+		if err := s.client.request(ctx, http.MethodPost, endpoint, req, &resp); err != nil {
+			return results, err
+		}
+
+		for _, r := range resp.Results {
+			result := &IngestResult{Index: start + r.Index, Accepted: r.Accepted}
+			if r.Error != nil {
+				result.Err = &APIError{
+					StatusCode: http.StatusUnprocessableEntity,
+					Message:    r.Error.Message,
+					Code:       r.Error.Code,
+				}
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// formatTelemetryDate formats t as RFC 3339, falling back to the raw
+// string when t is zero
+func formatTelemetryDate(t time.Time, raw string) string {
+	if t.IsZero() {
+		return raw
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseTelemetryDate returns t, or when it is zero parses raw as RFC 3339
+// or as a plain YYYY-MM-DD date
+func parseTelemetryDate(t time.Time, raw string) (time.Time, error) {
+	if !t.IsZero() {
+		return t, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", raw)
 }
 
 // GetHealth retrieves agent health metrics
@@ -307,59 +1886,129 @@ func (s *TelemetryService) GetHealth(ctx context.Context, agentID string) (*Heal
 
 // FederationService handles federation-related operations
 type FederationService struct {
-	client *Client
+	client             *Client
+	customCapabilities map[Capability]bool
+	strictCapabilities bool
 }
 
-// Discover discovers agents in the mesh
+// Discover discovers agents in the mesh. Unknown capabilities in opts are
+// logged, or rejected with WithStrictCapabilities.
 func (s *FederationService) Discover(ctx context.Context, opts *DiscoverOptions) ([]*Agent, error) {
 	var agents []*Agent
-	endpoint := "federation/discover"
+	query := url.Values{}
 	if opts != nil {
-		endpoint += "?"
+		if err := s.checkCapabilities(opts.Capabilities); err != nil {
+			return nil, err
+		}
 		if len(opts.Capabilities) > 0 {
-			for i, cap := range opts.Capabilities {
-				if i > 0 {
-					endpoint += ","
-				}
-				endpoint += cap
-			}
-			endpoint += "&"
+			query.Set("capabilities", strings.Join(opts.Capabilities, ","))
 		}
 		if opts.Region != "" {
-			endpoint += fmt.Sprintf("region=%s", opts.Region)
+			query.Set("region", opts.Region)
+		}
+		if opts.Status != "" {
+			query.Set("status", opts.Status)
+		}
+		if opts.Type != "" {
+			query.Set("type", opts.Type)
 		}
 	}
-	err := s.client.request(ctx, http.MethodGet, endpoint, nil, &agents)
+	err := s.client.request(ctx, http.MethodGet, withQuery("federation/discover", query), nil, &agents)
 	return agents, err
 }
 
-// Register registers an agent with federation
+// Register registers an agent with federation. Unknown capabilities in
+// config["capabilities"] are logged, or rejected with
+// WithStrictCapabilities.
 func (s *FederationService) Register(ctx context.Context, agentID string, config map[string]interface{}) (*FederationConfig, error) {
+	if err := s.checkCapabilities(capabilitiesFrom(config["capabilities"])); err != nil {
+		return nil, err
+	}
 	var fedConfig FederationConfig
 	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("federation/register/%s", agentID), config, &fedConfig)
 	return &fedConfig, err
 }
 
+// Deregister removes an agent from federation. Deregistering an agent that
+// isn't registered is not an error.
+func (s *FederationService) Deregister(ctx context.Context, agentID string) error {
+	err := s.client.request(ctx, http.MethodDelete, fmt.Sprintf("federation/register/%s", agentID), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// UpdateConfig changes the federation configuration of a registered
+// agent in place, keeping its discovery history. Only the fields set in
+// req are changed. Unknown capabilities are logged, or rejected with
+// WithStrictCapabilities.
+func (s *FederationService) UpdateConfig(ctx context.Context, agentID string, req *UpdateFederationConfigRequest) (*FederationConfig, error) {
+	if req != nil && req.Capabilities != nil {
+		if err := s.checkCapabilities(*req.Capabilities); err != nil {
+			return nil, err
+		}
+	}
+	var fedConfig FederationConfig
+	err := s.client.request(ctx, http.MethodPatch, fmt.Sprintf("federation/register/%s", agentID), req, &fedConfig)
+	return &fedConfig, err
+}
+
+// Heartbeat refreshes an agent's federation registration so that it
+// doesn't expire and drop out of discovery
+func (s *FederationService) Heartbeat(ctx context.Context, agentID string) (*FederationConfig, error) {
+	var fedConfig FederationConfig
+	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("federation/register/%s/heartbeat", agentID), nil, &fedConfig)
+	return &fedConfig, err
+}
+
 // MarketplaceService handles marketplace operations
 type MarketplaceService struct {
 	client *Client
 }
 
-// Browse browses the policy marketplace
-func (s *MarketplaceService) Browse(ctx context.Context, opts *MarketplaceOptions) ([]*MarketplacePolicy, error) {
-	var policies []*MarketplacePolicy
-	endpoint := "marketplace/policies"
+// Browse retrieves a page of marketplace policies. Pass the returned
+// NextCursor back in opts.Cursor to fetch the following page.
+func (s *MarketplaceService) Browse(ctx context.Context, opts *MarketplaceOptions) (*Page[*MarketplacePolicy], error) {
+	query := url.Values{}
 	if opts != nil {
-		endpoint += "?"
 		if opts.Category != "" {
-			endpoint += fmt.Sprintf("category=%s&", opts.Category)
+			query.Set("category", opts.Category)
 		}
 		if opts.Framework != "" {
-			endpoint += fmt.Sprintf("framework=%s", opts.Framework)
+			query.Set("framework", opts.Framework)
+		}
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Cursor != "" {
+			query.Set("cursor", opts.Cursor)
 		}
 	}
-	err := s.client.request(ctx, http.MethodGet, endpoint, nil, &policies)
-	return policies, err
+	return getPage[*MarketplacePolicy](ctx, s.client, withQuery("marketplace/policies", query), "policies")
+}
+
+// Get retrieves a marketplace policy, including its full rules which
+// Browse may truncate
+func (s *MarketplaceService) Get(ctx context.Context, policyID string) (*MarketplacePolicy, error) {
+	var policy MarketplacePolicy
+	err := s.client.request(ctx, http.MethodGet, fmt.Sprintf("marketplace/policies/%s", policyID), nil, &policy)
+	return &policy, err
+}
+
+// Rate submits a rating of 1 to 5 stars for a marketplace policy and
+// returns the policy's updated aggregate rating
+func (s *MarketplaceService) Rate(ctx context.Context, policyID string, stars int) (*Rating, error) {
+	if stars < 1 || stars > 5 {
+		return nil, &ValidationError{
+			Message: "invalid rating",
+			Fields:  map[string]string{"stars": "must be between 1 and 5"},
+		}
+	}
+	var rating Rating
+	req := map[string]int{"stars": stars}
+	err := s.client.request(ctx, http.MethodPost, fmt.Sprintf("marketplace/policies/%s/ratings", policyID), req, &rating)
+	return &rating, err
 }
 
 // Install installs a policy from the marketplace
@@ -370,6 +2019,17 @@ func (s *MarketplaceService) Install(ctx context.Context, policyID, agentID stri
 	return &policy, err
 }
 
+// Publish submits a policy to the marketplace. Policies published with
+// VisibilityDraft are only visible to the publishing account.
+func (s *MarketplaceService) Publish(ctx context.Context, req *PublishPolicyRequest) (*MarketplacePolicy, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	var policy MarketplacePolicy
+	err := s.client.request(ctx, http.MethodPost, "marketplace/policies", req, &policy)
+	return &policy, err
+}
+
 // AccountService handles account-related operations
 type AccountService struct {
 	client *Client
@@ -382,9 +2042,134 @@ func (s *AccountService) GetUsage(ctx context.Context) (*Usage, error) {
 	return &usage, err
 }
 
+// GetUsageSeries retrieves account usage over a date range broken down
+// into daily or weekly buckets
+func (s *AccountService) GetUsageSeries(ctx context.Context, opts *UsageSeriesOptions) ([]UsagePoint, error) {
+	var points []UsagePoint
+	query := url.Values{}
+	if opts != nil {
+		if !opts.Start.IsZero() {
+			query.Set("start_date", opts.Start.Format(time.RFC3339))
+		}
+		if !opts.End.IsZero() {
+			query.Set("end_date", opts.End.Format(time.RFC3339))
+		}
+		if opts.Granularity != "" {
+			query.Set("granularity", opts.Granularity)
+		}
+	}
+	err := s.client.request(ctx, http.MethodGet, withQuery("account/usage/series", query), nil, &points)
+	return points, err
+}
+
 // GetLimits retrieves account limits
 func (s *AccountService) GetLimits(ctx context.Context) (*Limits, error) {
 	var limits Limits
 	err := s.client.request(ctx, http.MethodGet, "account/limits", nil, &limits)
 	return &limits, err
 }
+
+// ProjectUsage projects this month's API calls to the end of the month
+// from the daily usage series so far, and compares the projection with the
+// account's APICallsPerMonth limit, so callers can alert before requests
+// start being throttled
+func (s *AccountService) ProjectUsage(ctx context.Context) (*UsageProjection, error) {
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	points, err := s.GetUsageSeries(ctx, &UsageSeriesOptions{
+		Start:       start,
+		End:         now,
+		Granularity: GranularityDay,
+	})
+	if err != nil {
+		return nil, err
+	}
+	limits, err := s.GetLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return projectUsage(points, limits, start, now), nil
+}
+
+// projectUsage extrapolates the API calls in points, made between start
+// and now, linearly to the end of start's month
+func projectUsage(points []UsagePoint, limits *Limits, start, now time.Time) *UsageProjection {
+	projection := &UsageProjection{
+		PeriodStart: start,
+		PeriodEnd:   start.AddDate(0, 1, 0),
+		Limit:       limits.APICallsPerMonth,
+	}
+	for _, p := range points {
+		projection.APICalls += p.APICalls
+	}
+
+	projection.ProjectedAPICalls = projection.APICalls
+	if elapsed := now.Sub(start); elapsed > 0 {
+		days := elapsed.Hours() / 24
+		projection.DailyRate = float64(projection.APICalls) / days
+		remaining := projection.PeriodEnd.Sub(now).Hours() / 24
+		projection.ProjectedAPICalls += int(math.Round(projection.DailyRate * remaining))
+	}
+	projection.WillExceed = projection.Limit > 0 && projection.ProjectedAPICalls > projection.Limit
+	return projection
+}
+
+// GetBillingHistory retrieves invoices, optionally limited to a billing
+// date range
+func (s *AccountService) GetBillingHistory(ctx context.Context, opts *BillingHistoryOptions) ([]*Invoice, error) {
+	var invoices []*Invoice
+	query := url.Values{}
+	if opts != nil {
+		if !opts.Start.IsZero() {
+			query.Set("start_date", opts.Start.Format(time.RFC3339))
+		}
+		if !opts.End.IsZero() {
+			query.Set("end_date", opts.End.Format(time.RFC3339))
+		}
+		if opts.Status != "" {
+			query.Set("status", opts.Status)
+		}
+	}
+	err := s.client.request(ctx, http.MethodGet, withQuery("account/invoices", query), nil, &invoices)
+	return invoices, err
+}
+
+// GetInvoice retrieves an invoice with its line items
+func (s *AccountService) GetInvoice(ctx context.Context, invoiceID string) (*Invoice, error) {
+	var invoice Invoice
+	err := s.client.request(ctx, http.MethodGet, fmt.Sprintf("account/invoices/%s", invoiceID), nil, &invoice)
+	return &invoice, err
+}
+
+// ListMembers retrieves the account's team members, including pending
+// invitations
+func (s *AccountService) ListMembers(ctx context.Context) ([]*Member, error) {
+	var members []*Member
+	err := s.client.request(ctx, http.MethodGet, "account/members", nil, &members)
+	return members, err
+}
+
+// InviteMember invites email to the account with the given role. The
+// returned member stays "invited" until the invitation is accepted.
+func (s *AccountService) InviteMember(ctx context.Context, email string, role MemberRole) (*Member, error) {
+	f := fieldErrors{}
+	f.require("email", email != "")
+	f.require("role", role != "")
+	if err := f.err("member invitation"); err != nil {
+		return nil, err
+	}
+	var member Member
+	req := map[string]interface{}{"email": email, "role": role}
+	err := s.client.request(ctx, http.MethodPost, "account/members", req, &member)
+	return &member, err
+}
+
+// RemoveMember removes a team member or revokes a pending invitation.
+// Removing a member that doesn't exist is not an error.
+func (s *AccountService) RemoveMember(ctx context.Context, memberID string) error {
+	err := s.client.request(ctx, http.MethodDelete, fmt.Sprintf("account/members/%s", memberID), nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
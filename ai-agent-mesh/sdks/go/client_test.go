@@ -0,0 +1,114 @@
+package agentmesh
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRetryTestClient returns a client for url that retries up to three
+// times without waiting between attempts
+func newRetryTestClient(url string, opts ...Option) *Client {
+	opts = append([]Option{
+		WithBaseURL(url),
+		WithMaxRetries(3),
+		WithBackoff(time.Millisecond, time.Millisecond, 1, false),
+	}, opts...)
+	return NewClient("test-key", opts...)
+}
+
+func TestRetryClassification(t *testing.T) {
+	slow := func(hits *atomic.Int32) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			select {
+			case <-time.After(200 * time.Millisecond):
+			case <-r.Context().Done():
+			}
+			w.Write([]byte(`{}`))
+		}
+	}
+
+	t.Run("timed out POST is not replayed", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(slow(&hits))
+		defer srv.Close()
+
+		client := newRetryTestClient(srv.URL, WithTimeout(50*time.Millisecond))
+		_, err := client.Workflows.Execute(context.Background(), "wf_1", nil)
+
+		require.Error(t, err)
+		assert.EqualValues(t, 1, hits.Load())
+	})
+
+	t.Run("timed out POST with idempotency key is retried", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(slow(&hits))
+		defer srv.Close()
+
+		client := newRetryTestClient(srv.URL, WithTimeout(50*time.Millisecond))
+		ctx := WithIdempotencyKey(context.Background(), "run-1")
+		_, err := client.Workflows.Execute(ctx, "wf_1", nil)
+
+		require.Error(t, err)
+		assert.EqualValues(t, 4, hits.Load())
+	})
+
+	t.Run("timed out GET is retried", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(slow(&hits))
+		defer srv.Close()
+
+		client := newRetryTestClient(srv.URL, WithTimeout(50*time.Millisecond))
+		_, err := client.Agents.Get(context.Background(), "agent_1")
+
+		require.Error(t, err)
+		assert.EqualValues(t, 4, hits.Load())
+	})
+
+	t.Run("POST that was never sent is retryable", func(t *testing.T) {
+		// Nothing listens on the address once the listener is closed, so
+		// the attempt fails to dial
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+
+		client := newRetryTestClient("http://" + addr)
+		retry, err := client.do(context.Background(), http.MethodPost, "http://"+addr+"/workflows/wf_1/execute", nil, http.Header{}, nil)
+
+		require.Error(t, err)
+		assert.True(t, retry)
+	})
+}
+
+func TestPingUsesRequestPipeline(t *testing.T) {
+	var hits atomic.Int32
+	var gotHeader, gotCorrelationID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		gotHeader = r.Header.Get("X-Tenant")
+		gotCorrelationID = r.Header.Get("X-Correlation-ID")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := newRetryTestClient(srv.URL)
+	ctx := WithHeader(WithCorrelationID(context.Background(), "trace-1"), "X-Tenant", "acme")
+	err := client.Ping(ctx)
+
+	require.ErrorIs(t, err, ErrServer)
+	assert.EqualValues(t, 1, hits.Load(), "Ping must not be retried")
+	assert.Equal(t, "acme", gotHeader)
+	assert.Equal(t, "trace-1", gotCorrelationID)
+	stats := client.Stats()
+	assert.EqualValues(t, 1, stats.Requests)
+	assert.EqualValues(t, 1, stats.Errors)
+}
@@ -0,0 +1,94 @@
+package agentmesh
+
+// Clone returns a deep copy of the agent, so its Config can be modified
+// without affecting a cached or shared original
+func (a *Agent) Clone() *Agent {
+	if a == nil {
+		return nil
+	}
+	clone := *a
+	clone.Config = copyMap(a.Config)
+	return &clone
+}
+
+// Clone returns a deep copy of the workflow, so its Definition can be
+// modified without affecting a cached or shared original
+func (w *Workflow) Clone() *Workflow {
+	if w == nil {
+		return nil
+	}
+	clone := *w
+	clone.Definition = copyMap(w.Definition)
+	if w.LastExecuted != nil {
+		lastExecuted := *w.LastExecuted
+		clone.LastExecuted = &lastExecuted
+	}
+	return &clone
+}
+
+// Clone returns a deep copy of the policy, so its Rules can be modified
+// without affecting a cached or shared original
+func (p *Policy) Clone() *Policy {
+	if p == nil {
+		return nil
+	}
+	clone := *p
+	clone.Rules = copyMap(p.Rules)
+	return &clone
+}
+
+// copyMap deep-copies m, preserving nil
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = copyValue(v)
+	}
+	return out
+}
+
+// copyValue deep-copies the maps and slices that decoded JSON and typical
+// hand-built configs are made of. Other values are copied as is.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return copyMap(v)
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = copyValue(item)
+		}
+		return out
+	case []map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		out := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			out[i] = copyMap(item)
+		}
+		return out
+	case map[string]string:
+		if v == nil {
+			return v
+		}
+		out := make(map[string]string, len(v))
+		for k, s := range v {
+			out[k] = s
+		}
+		return out
+	case []string:
+		if v == nil {
+			return v
+		}
+		out := make([]string, len(v))
+		copy(out, v)
+		return out
+	}
+	return v
+}
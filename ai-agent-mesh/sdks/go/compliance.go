@@ -0,0 +1,78 @@
+package agentmesh
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// complianceCSVHeader is the header row written by WriteComplianceCSV
+var complianceCSVHeader = []string{"agent_id", "policy_name", "severity", "checked_at", "details"}
+
+// SeverityCounts returns the number of violations of each severity
+func (r *ComplianceReport) SeverityCounts() map[string]int {
+	counts := map[string]int{}
+	for _, v := range r.Violations {
+		counts[v.Severity]++
+	}
+	return counts
+}
+
+// WriteCSV writes the report as CSV with one row per violation
+func (r *ComplianceReport) WriteCSV(w io.Writer) error {
+	return WriteComplianceCSV(w, r)
+}
+
+// WriteJSON writes the report as an indented JSON document with a summary
+// of violation counts per severity
+func (r *ComplianceReport) WriteJSON(w io.Writer) error {
+	doc := struct {
+		AgentID   string    `json:"agentId"`
+		Compliant bool      `json:"compliant"`
+		CheckedAt time.Time `json:"checkedAt"`
+		Summary   struct {
+			Violations int            `json:"violations"`
+			BySeverity map[string]int `json:"bySeverity"`
+		} `json:"summary"`
+		Violations []PolicyViolation `json:"violations"`
+	}{
+		AgentID:    r.AgentID,
+		Compliant:  r.Compliant,
+		CheckedAt:  r.CheckedAt,
+		Violations: r.Violations,
+	}
+	doc.Summary.Violations = len(r.Violations)
+	doc.Summary.BySeverity = r.SeverityCounts()
+	if doc.Violations == nil {
+		doc.Violations = []PolicyViolation{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// WriteComplianceCSV writes several reports as one CSV document with a
+// header row and one row per violation. A violation's details are encoded
+// as JSON in the last column.
+func WriteComplianceCSV(w io.Writer, reports ...*ComplianceReport) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(complianceCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		checkedAt := r.CheckedAt.Format(time.RFC3339)
+		for _, v := range r.Violations {
+			details, err := json.Marshal(v.Details)
+			if err != nil {
+				return err
+			}
+			if err := cw.Write([]string{r.AgentID, v.PolicyName, v.Severity, checkedAt, string(details)}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
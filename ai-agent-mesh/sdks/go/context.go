@@ -0,0 +1,97 @@
+package agentmesh
+
+import (
+	"context"
+	"net/http"
+)
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey returns a context that sends key as the
+// Idempotency-Key header on requests made with it. The server uses the key
+// to recognise replays, which makes it safe for the client to retry POST
+// requests after 5xx responses; every retry reuses the same key.
+//
+//	ctx = agentmesh.WithIdempotencyKey(ctx, "provision-agent-42")
+//	agent, err := client.Agents.Create(ctx, req)
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+type apiKeyKey struct{}
+
+// WithAPIKey returns a context whose requests authenticate with key instead
+// of the client's own API key or OAuth2 token, for proxies serving many
+// tenants from one client, sharing its connections and retry settings.
+//
+//	ctx = agentmesh.WithAPIKey(ctx, tenant.MeshAPIKey)
+//	agents, err := client.Agents.List(ctx, nil)
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, key)
+}
+
+func apiKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(apiKeyKey{}).(string)
+	return key
+}
+
+type headerKey struct{}
+
+// WithHeader returns a context that adds the header key: value to requests
+// made with it, leaving other requests unaffected. Calls accumulate, so
+// several headers can be attached.
+//
+//	ctx = agentmesh.WithHeader(ctx, "X-Tenant-ID", tenantID)
+//	agents, err := client.Agents.List(ctx, nil)
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	header := headerFrom(ctx).Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Add(key, value)
+	return context.WithValue(ctx, headerKey{}, header)
+}
+
+// headerFrom returns the headers attached with WithHeader; it must not be
+// modified
+func headerFrom(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerKey{}).(http.Header)
+	return header
+}
+
+// correlationIDHeader carries the caller's correlation ID to the API
+const correlationIDHeader = "X-Correlation-ID"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context whose requests carry id in the
+// X-Correlation-ID header, tying them to the caller's own traces
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// WithCorrelationIDFromContext makes the client read the correlation ID of
+// each request from its context with fn, for applications that already
+// keep one under their own context key. An ID set with WithCorrelationID
+// takes precedence.
+func WithCorrelationIDFromContext(fn func(ctx context.Context) string) Option {
+	return func(c *Config) {
+		c.CorrelationIDFromContext = fn
+	}
+}
+
+// correlationID returns the correlation ID to send for ctx, if any
+func (c *Client) correlationID(ctx context.Context) string {
+	if id, ok := ctx.Value(correlationIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	if c.correlationIDFrom != nil {
+		return c.correlationIDFrom(ctx)
+	}
+	return ""
+}
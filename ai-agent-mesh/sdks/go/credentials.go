@@ -0,0 +1,35 @@
+package agentmesh
+
+import "context"
+
+// CredentialProvider supplies the bearer credential sent with each request
+// attempt, for credentials fetched dynamically from e.g. Vault, AWS
+// Secrets Manager or a workload identity. Token is called before every
+// attempt, including retries, so providers of short-lived credentials
+// should cache them until shortly before they expire. It may be called
+// concurrently.
+//
+// If the provider also has an Invalidate() method, it is called whenever
+// the API rejects a credential with 401 Unauthorized, so a cached
+// credential that was revoked can be dropped and fetched anew.
+type CredentialProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// CredentialProviderFunc adapts a function to a CredentialProvider
+type CredentialProviderFunc func(ctx context.Context) (string, error)
+
+// Token calls f
+func (f CredentialProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithCredentialProvider authenticates every request with the credential
+// returned by provider instead of the API key, which may then be empty.
+// It takes precedence over WithOAuth2; a key attached to a request's
+// context with WithAPIKey still overrides both.
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return func(c *Config) {
+		c.CredentialProvider = provider
+	}
+}
@@ -0,0 +1,48 @@
+package agentmesh
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// WithStrictDecoding makes decoding a response fail when it has fields the
+// SDK's types don't declare, to catch drift between the SDK and the API
+// early. Fields of untyped maps such as Agent.Config are not affected.
+func WithStrictDecoding() Option {
+	return func(c *Config) {
+		c.DisallowUnknownFields = true
+	}
+}
+
+// WithUseNumber decodes numbers in untyped values, such as Agent.Config or
+// TelemetryEvent.Payload, as json.Number instead of float64, so integers
+// beyond 2^53 such as 64-bit IDs keep their precision
+func WithUseNumber() Option {
+	return func(c *Config) {
+		c.UseNumber = true
+	}
+}
+
+// decodeOptions configures the decoders of API responses
+type decodeOptions struct {
+	disallowUnknownFields bool
+	useNumber             bool
+}
+
+// newDecoder returns a decoder for a response body
+func (c *Client) newDecoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if c.decode.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if c.decode.useNumber {
+		dec.UseNumber()
+	}
+	return dec
+}
+
+// unmarshal decodes data, already read from a response, into v
+func (c *Client) unmarshal(data []byte, v interface{}) error {
+	return c.newDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,137 @@
+package agentmesh
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Kinds of FieldChange
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// FieldChange is one difference between an agent and its desired spec
+type FieldChange struct {
+	// Path locates the field, e.g. "type" or "config.tools[1].name"
+	Path string
+	// Kind is ChangeAdded, ChangeRemoved or ChangeModified
+	Kind string
+	// Old is the current value; nil when the field is added
+	Old interface{}
+	// New is the desired value; nil when the field is removed
+	New interface{}
+}
+
+// String formats the change as a line of a plan, e.g.
+// "~ config.temperature: 0.7 => 0.2"
+func (c FieldChange) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %v", c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %v", c.Path, c.Old)
+	}
+	return fmt.Sprintf("~ %s: %v => %v", c.Path, c.Old, c.New)
+}
+
+// DiffAgent returns the changes AgentService.Apply would make to bring
+// current in line with desired, sorted by path, or none if it already
+// matches. Config maps are compared recursively, so each changed nested
+// value is reported on its own. A nil current reports every field of
+// desired as added, as for an agent that doesn't exist yet.
+func DiffAgent(current *Agent, desired *AgentSpec) ([]FieldChange, error) {
+	if current == nil {
+		current = &Agent{}
+	}
+	if desired == nil {
+		desired = &AgentSpec{}
+	}
+	// Normalize both sides so e.g. an int in a hand-built spec equals the
+	// float64 the API decodes it to
+	currentConfig, err := normalizeConfig(current.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize current config: %w", err)
+	}
+	desiredConfig, err := normalizeConfig(desired.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize desired config: %w", err)
+	}
+
+	var changes []FieldChange
+	diffString(&changes, "name", current.Name, desired.Name)
+	diffString(&changes, "type", current.Type, desired.Type)
+	if desired.Status != "" {
+		diffString(&changes, "status", current.Status, desired.Status)
+	}
+	diffMap(&changes, "config", currentConfig, desiredConfig)
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// diffString appends the change between two values of a string field,
+// treating empty as unset
+func diffString(changes *[]FieldChange, path, old, new string) {
+	switch {
+	case old == new:
+	case old == "":
+		*changes = append(*changes, FieldChange{Path: path, Kind: ChangeAdded, New: new})
+	case new == "":
+		*changes = append(*changes, FieldChange{Path: path, Kind: ChangeRemoved, Old: old})
+	default:
+		*changes = append(*changes, FieldChange{Path: path, Kind: ChangeModified, Old: old, New: new})
+	}
+}
+
+// diffValue appends the changes between old and new at path, recursing
+// into maps and slices
+func diffValue(changes *[]FieldChange, path string, old, new interface{}) {
+	switch o := old.(type) {
+	case map[string]interface{}:
+		if n, ok := new.(map[string]interface{}); ok {
+			diffMap(changes, path, o, n)
+			return
+		}
+	case []interface{}:
+		if n, ok := new.([]interface{}); ok {
+			diffSlice(changes, path, o, n)
+			return
+		}
+	}
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, FieldChange{Path: path, Kind: ChangeModified, Old: old, New: new})
+	}
+}
+
+func diffMap(changes *[]FieldChange, path string, old, new map[string]interface{}) {
+	for key, o := range old {
+		n, ok := new[key]
+		if !ok {
+			*changes = append(*changes, FieldChange{Path: path + "." + key, Kind: ChangeRemoved, Old: o})
+			continue
+		}
+		diffValue(changes, path+"."+key, o, n)
+	}
+	for key, n := range new {
+		if _, ok := old[key]; !ok {
+			*changes = append(*changes, FieldChange{Path: path + "." + key, Kind: ChangeAdded, New: n})
+		}
+	}
+}
+
+func diffSlice(changes *[]FieldChange, path string, old, new []interface{}) {
+	for i := 0; i < len(old) || i < len(new); i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(new):
+			*changes = append(*changes, FieldChange{Path: itemPath, Kind: ChangeRemoved, Old: old[i]})
+		case i >= len(old):
+			*changes = append(*changes, FieldChange{Path: itemPath, Kind: ChangeAdded, New: new[i]})
+		default:
+			diffValue(changes, itemPath, old[i], new[i])
+		}
+	}
+}
@@ -1,51 +1,227 @@
 package agentmesh
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors for use with errors.Is
+var (
+	// ErrUnauthorized matches *AuthenticationError
+	ErrUnauthorized = errors.New("agentmesh: unauthorized")
+	// ErrNotFound matches *NotFoundError
+	ErrNotFound = errors.New("agentmesh: not found")
+	// ErrRateLimited matches *RateLimitError
+	ErrRateLimited = errors.New("agentmesh: rate limited")
+	// ErrValidation matches *ValidationError
+	ErrValidation = errors.New("agentmesh: validation failed")
+	// ErrConflict matches *ConflictError
+	ErrConflict = errors.New("agentmesh: conflict")
+	// ErrServer matches *ServerError
+	ErrServer = errors.New("agentmesh: server error")
+	// ErrNotHealthy is returned when an agent doesn't become healthy in time
+	ErrNotHealthy = errors.New("agentmesh: agent not healthy")
+	// ErrWorkflowFailed is returned when a workflow execution ends failed
+	// or cancelled
+	ErrWorkflowFailed = errors.New("agentmesh: workflow execution failed")
+)
 
 // APIError represents a generic API error
 type APIError struct {
 	StatusCode int
 	Message    string
 	Code       string
+	RequestID  string
 }
 
 func (e *APIError) Error() string {
-	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+	return withRequestID(fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message), e.RequestID)
 }
 
 // AuthenticationError represents an authentication error
 type AuthenticationError struct {
-	Message string
+	Message   string
+	RequestID string
 }
 
 func (e *AuthenticationError) Error() string {
-	return fmt.Sprintf("authentication error: %s", e.Message)
+	return withRequestID(fmt.Sprintf("authentication error: %s", e.Message), e.RequestID)
+}
+
+// Is reports whether target is ErrUnauthorized
+func (e *AuthenticationError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// As converts the error to an *APIError
+func (e *AuthenticationError) As(target interface{}) bool {
+	return asAPIError(target, http.StatusUnauthorized, e.Message, "", e.RequestID)
 }
 
 // NotFoundError represents a not found error
 type NotFoundError struct {
-	Message string
+	Message   string
+	RequestID string
 }
 
 func (e *NotFoundError) Error() string {
-	return fmt.Sprintf("not found: %s", e.Message)
+	return withRequestID(fmt.Sprintf("not found: %s", e.Message), e.RequestID)
+}
+
+// Is reports whether target is ErrNotFound
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// As converts the error to an *APIError
+func (e *NotFoundError) As(target interface{}) bool {
+	return asAPIError(target, http.StatusNotFound, e.Message, "", e.RequestID)
 }
 
 // RateLimitError represents a rate limit error
 type RateLimitError struct {
 	Message string
+	// RetryAfter is how long the server asked clients to wait before
+	// retrying, or zero if no Retry-After header was sent
+	RetryAfter time.Duration
+	RequestID  string
 }
 
 func (e *RateLimitError) Error() string {
-	return fmt.Sprintf("rate limit exceeded: %s", e.Message)
+	return withRequestID(fmt.Sprintf("rate limit exceeded: %s", e.Message), e.RequestID)
 }
 
-// ValidationError represents a validation error
+// Is reports whether target is ErrRateLimited
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// As converts the error to an *APIError
+func (e *RateLimitError) As(target interface{}) bool {
+	return asAPIError(target, http.StatusTooManyRequests, e.Message, "", e.RequestID)
+}
+
+// ValidationError represents a validation error, detected either before
+// sending a request or by the server
 type ValidationError struct {
-	Message string
-	Fields  map[string]string
+	// StatusCode is the status the server rejected the request with, 400
+	// or 422, or zero if the error was detected before sending it
+	StatusCode int
+	Message    string
+	// Fields maps each invalid field to what is wrong with it
+	Fields    map[string]string
+	RequestID string
 }
 
 func (e *ValidationError) Error() string {
-	return fmt.Sprintf("validation error: %s", e.Message)
+	return withRequestID(fmt.Sprintf("validation error: %s", e.Message), e.RequestID)
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// As converts the error to an *APIError. Errors detected before sending
+// the request are reported as 400 Bad Request.
+func (e *ValidationError) As(target interface{}) bool {
+	return asAPIError(target, e.status(), e.Message, "", e.RequestID)
+}
+
+// status returns the error's status code, defaulting to 400
+func (e *ValidationError) status() int {
+	if e.StatusCode == 0 {
+		return http.StatusBadRequest
+	}
+	return e.StatusCode
+}
+
+// ConflictError is returned when a conditional request's If-Match
+// precondition fails because the resource was changed by another writer.
+// Fetch it again and reapply the change.
+type ConflictError struct {
+	Message   string
+	RequestID string
+}
+
+func (e *ConflictError) Error() string {
+	return withRequestID(fmt.Sprintf("conflict: %s", e.Message), e.RequestID)
+}
+
+// Is reports whether target is ErrConflict
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// As converts the error to an *APIError
+func (e *ConflictError) As(target interface{}) bool {
+	return asAPIError(target, http.StatusPreconditionFailed, e.Message, "", e.RequestID)
+}
+
+// ServerError represents a 5xx response: a fault on the server side
+// rather than in the request
+type ServerError struct {
+	StatusCode int
+	Message    string
+	Code       string
+	RequestID  string
+}
+
+func (e *ServerError) Error() string {
+	return withRequestID(fmt.Sprintf("server error (%d): %s", e.StatusCode, e.Message), e.RequestID)
+}
+
+// Is reports whether target is ErrServer
+func (e *ServerError) Is(target error) bool {
+	return target == ErrServer
+}
+
+// As converts the error to an *APIError
+func (e *ServerError) As(target interface{}) bool {
+	return asAPIError(target, e.StatusCode, e.Message, e.Code, e.RequestID)
+}
+
+// RetryError wraps the error of a call that was retried and still failed,
+// recording how hard the client tried. Calls that fail on the first
+// attempt return their error unwrapped. errors.Is and errors.As see
+// through it to the error of the last attempt.
+type RetryError struct {
+	// Attempts is the number of attempts made, including the first
+	Attempts int
+	// Elapsed is the time from the first attempt to giving up, including
+	// the waits between attempts
+	Elapsed time.Duration
+	// Err is the error of the last attempt
+	Err error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts in %s)", e.Err, e.Attempts, e.Elapsed.Round(time.Millisecond))
+}
+
+// Unwrap returns the error of the last attempt
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// withRequestID appends the server request ID to msg, if there is one
+func withRequestID(msg, requestID string) string {
+	if requestID == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s (request ID: %s)", msg, requestID)
+}
+
+// asAPIError fills target with an equivalent *APIError if target is an
+// **APIError, so errors.As(err, &apiErr) works for every HTTP error type.
+// code is the server's error code, empty for types that don't carry one.
+func asAPIError(target interface{}, statusCode int, message, code, requestID string) bool {
+	apiErr, ok := target.(**APIError)
+	if !ok {
+		return false
+	}
+	*apiErr = &APIError{StatusCode: statusCode, Message: message, Code: code, RequestID: requestID}
+	return true
 }
@@ -0,0 +1,53 @@
+package agentmesh
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrorAs(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"server 422", &ValidationError{StatusCode: 422, Message: "bad config", RequestID: "req_1"}, 422},
+		{"server 400", &ValidationError{StatusCode: 400, Message: "bad config", RequestID: "req_1"}, 400},
+		{"client-side", &ValidationError{Message: "bad config"}, 400},
+		{"wrapped", fmt.Errorf("create agent: %w", &ValidationError{StatusCode: 422, Message: "bad config"}), 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr *APIError
+			require.True(t, errors.As(tt.err, &apiErr))
+			assert.Equal(t, tt.wantStatus, apiErr.StatusCode)
+			assert.Equal(t, "bad config", apiErr.Message)
+			assert.True(t, errors.Is(tt.err, ErrValidation))
+		})
+	}
+}
+
+func TestErrorsAsAPIError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want APIError
+	}{
+		{"authentication", &AuthenticationError{Message: "m", RequestID: "req_1"}, APIError{StatusCode: 401, Message: "m", RequestID: "req_1"}},
+		{"not found", &NotFoundError{Message: "m", RequestID: "req_1"}, APIError{StatusCode: 404, Message: "m", RequestID: "req_1"}},
+		{"rate limit", &RateLimitError{Message: "m", RequestID: "req_1"}, APIError{StatusCode: 429, Message: "m", RequestID: "req_1"}},
+		{"conflict", &ConflictError{Message: "m", RequestID: "req_1"}, APIError{StatusCode: 412, Message: "m", RequestID: "req_1"}},
+		{"server", &ServerError{StatusCode: 503, Message: "m", Code: "unavailable", RequestID: "req_1"}, APIError{StatusCode: 503, Message: "m", Code: "unavailable", RequestID: "req_1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr *APIError
+			require.True(t, errors.As(fmt.Errorf("call: %w", tt.err), &apiErr))
+			assert.Equal(t, tt.want, *apiErr)
+		})
+	}
+}
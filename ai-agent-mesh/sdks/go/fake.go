@@ -0,0 +1,154 @@
+package agentmesh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// fakeBaseURL is the base URL of clients created by NewTestClient
+const fakeBaseURL = "http://agentmesh.test"
+
+// Fake is an in-memory stand-in for the AI-Agent Mesh API. It answers
+// requests from programmed stubs and records every call, so code built on
+// *Client can be tested without network access.
+type Fake struct {
+	mu    sync.Mutex
+	stubs map[string]*fakeStub
+	calls []*RecordedCall
+}
+
+type fakeStub struct {
+	status int
+	body   []byte
+	header http.Header
+}
+
+// RecordedCall is a request received by a Fake
+type RecordedCall struct {
+	Method string
+	// Path is relative to the API base URL, e.g. "agents/agent_123"
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// DecodeBody decodes the JSON request body into v
+func (c *RecordedCall) DecodeBody(v interface{}) error {
+	return json.Unmarshal(c.Body, v)
+}
+
+// NewTestClient returns a client whose requests are served by the returned
+// Fake. Retries are disabled unless opts re-enable them.
+//
+//	client, fake := agentmesh.NewTestClient()
+//	fake.Stub(http.MethodGet, "agents/agent_123", http.StatusOK, &agentmesh.Agent{ID: "agent_123"})
+func NewTestClient(opts ...Option) (*Client, *Fake) {
+	fake := &Fake{stubs: map[string]*fakeStub{}}
+	opts = append([]Option{
+		WithBaseURL(fakeBaseURL),
+		WithMaxRetries(0),
+		WithHTTPClient(&http.Client{Transport: fake}),
+	}, opts...)
+	return NewClient("test-api-key", opts...), fake
+}
+
+// Stub programs the response to requests for method and path. path is
+// relative to the API base URL and excludes the query string. body is
+// encoded as JSON unless it is nil, a string or a []byte.
+func (f *Fake) Stub(method, path string, status int, body interface{}) {
+	var data []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		data = b
+	case string:
+		data = []byte(b)
+	default:
+		var err error
+		if data, err = json.Marshal(b); err != nil {
+			panic(fmt.Sprintf("agentmesh: cannot encode stub body: %v", err))
+		}
+	}
+
+	header := http.Header{}
+	if data != nil {
+		header.Set("Content-Type", "application/json")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs[fakeKey(method, path)] = &fakeStub{status: status, body: data, header: header}
+}
+
+// StubError programs an API error response with the given message
+func (f *Fake) StubError(method, path string, status int, message string) {
+	f.Stub(method, path, status, map[string]string{"message": message})
+}
+
+// Calls returns the requests received so far, in order
+func (f *Fake) Calls() []*RecordedCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*RecordedCall(nil), f.calls...)
+}
+
+// Reset removes all stubs and recorded calls
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs = map[string]*fakeStub{}
+	f.calls = nil
+}
+
+// RoundTrip implements http.RoundTripper. Requests without a stub get a
+// 404 response.
+func (f *Fake) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	call := &RecordedCall{
+		Method: req.Method,
+		Path:   path,
+		Query:  req.URL.Query(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	stub, ok := f.stubs[fakeKey(req.Method, path)]
+	f.mu.Unlock()
+
+	if !ok {
+		msg, _ := json.Marshal(map[string]string{"message": fmt.Sprintf("no stub for %s %s", req.Method, path)})
+		stub = &fakeStub{status: http.StatusNotFound, body: msg, header: http.Header{"Content-Type": {"application/json"}}}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", stub.status, http.StatusText(stub.status)),
+		StatusCode:    stub.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        stub.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(stub.body)),
+		ContentLength: int64(len(stub.body)),
+		Request:       req,
+	}, nil
+}
+
+func fakeKey(method, path string) string {
+	return method + " " + strings.TrimPrefix(path, "/")
+}
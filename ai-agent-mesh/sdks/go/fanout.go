@@ -0,0 +1,69 @@
+package agentmesh
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultConcurrency bounds fan-out helpers when no limit is given
+const defaultConcurrency = 8
+
+// FanOutResult is the outcome of one call made by FanOut
+type FanOutResult[R any] struct {
+	Value R
+	// Err is the error of the call, or the context error if ctx was done
+	// before the call was started
+	Err error
+}
+
+// FanOut calls fn for every item with at most concurrency calls in flight
+// (a default limit if zero) and returns their results in the same order as
+// items. Once ctx is done no further calls are started; the remaining
+// items get the context error. fn is called concurrently, so it must be
+// safe for that, as every Client method is.
+//
+//	results := agentmesh.FanOut(ctx, agentIDs, 20, func(ctx context.Context, id string) (*agentmesh.Agent, error) {
+//		return client.Agents.Update(ctx, id, req)
+//	})
+func FanOut[T, R any](ctx context.Context, items []T, concurrency int, fn func(ctx context.Context, item T) (R, error)) []FanOutResult[R] {
+	results := make([]FanOutResult[R], len(items))
+	fanOut(ctx, len(items), concurrency, func(i int) {
+		value, err := fn(ctx, items[i])
+		results[i] = FanOutResult[R]{Value: value, Err: err}
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	return results
+}
+
+// fanOut calls fn for each index in [0, n) with at most concurrency calls
+// in flight, and waits for them to finish. Once ctx is done no further
+// calls are started and fn is instead called with the context error via
+// skip, so every index is accounted for.
+func fanOut(ctx context.Context, n, concurrency int, fn func(i int), skip func(i int, err error)) {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			skip(i, ctx.Err())
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			<-sem
+			skip(i, err)
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,57 @@
+package agentmesh
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestInterceptor is called with every outgoing request just before it
+// is sent and may modify its headers. Returning an error aborts the
+// request with that error. It may be called concurrently.
+type RequestInterceptor func(req *http.Request) error
+
+// ResponseInterceptor is called after every round trip with the request,
+// the response and how long the round trip took. resp is nil when err
+// reports a connection-level failure. The response body must not be
+// consumed. It may be called concurrently.
+type ResponseInterceptor func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+
+// WithRequestInterceptor adds a request interceptor. Interceptors run in
+// the order they were added, once per attempt.
+func WithRequestInterceptor(fn RequestInterceptor) Option {
+	return func(c *Config) {
+		c.RequestInterceptors = append(c.RequestInterceptors, fn)
+	}
+}
+
+// WithResponseInterceptor adds a response interceptor. Interceptors run in
+// the order they were added, once per attempt.
+func WithResponseInterceptor(fn ResponseInterceptor) Option {
+	return func(c *Config) {
+		c.ResponseInterceptors = append(c.ResponseInterceptors, fn)
+	}
+}
+
+// send runs the request interceptors, performs the round trip with
+// httpClient, logs it, records rate-limit headers and then runs the
+// response interceptors
+func (c *Client) send(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	for _, intercept := range c.requestInterceptors {
+		if err := intercept(req); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	elapsed := time.Since(start)
+	c.logRoundTrip(req, resp, err, elapsed)
+	if resp != nil {
+		c.recordRateLimit(resp)
+	}
+
+	for _, intercept := range c.responseInterceptors {
+		intercept(req, resp, err, elapsed)
+	}
+	return resp, err
+}
@@ -0,0 +1,62 @@
+package agentmesh
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// redactedHeaders are never written to logs verbatim
+var redactedHeaders = []string{"Authorization", "Cookie", "X-Api-Key"}
+
+// WithLogger makes the client log every request attempt to logger:
+// successful round trips at debug level and failures at warn level.
+// Credentials in request headers are redacted.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// logRoundTrip logs the outcome of a single request attempt
+func (c *Client) logRoundTrip(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Duration("duration", elapsed),
+		slog.Any("headers", redactHeaders(req.Header)),
+	}
+	level := slog.LevelDebug
+	msg := "agentmesh request"
+	if err != nil {
+		level = slog.LevelWarn
+		msg = "agentmesh request failed"
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.String("request_id", resp.Header.Get(requestIDHeader)),
+		)
+		if resp.StatusCode >= 400 {
+			level = slog.LevelWarn
+			msg = "agentmesh request failed"
+		}
+	}
+	c.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// redactHeaders returns a copy of h with credential values masked
+func redactHeaders(h http.Header) http.Header {
+	redacted := h.Clone()
+	for _, key := range redactedHeaders {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, "REDACTED")
+		}
+	}
+	return redacted
+}
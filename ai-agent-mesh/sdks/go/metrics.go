@@ -0,0 +1,61 @@
+package agentmesh
+
+import (
+	"strings"
+	"time"
+)
+
+// RequestObservation describes one API call, including all of its retries
+type RequestObservation struct {
+	Method string
+	// Endpoint is the request path relative to the base URL, without the
+	// query string. It contains resource IDs, so recorders exporting it as
+	// a label may want to normalize it.
+	Endpoint string
+	// StatusCode is the status of the last attempt, or zero if it failed
+	// before a response was received
+	StatusCode int
+	// Latency is the total time taken, including retries and the waits
+	// between them
+	Latency time.Duration
+	// Retries is the number of attempts after the first
+	Retries int
+	// Err is the error returned to the caller, or nil on success
+	Err error
+}
+
+// MetricsRecorder receives an observation for every API call made through
+// the client, e.g. to export request rate, errors and duration to
+// Prometheus or StatsD. It may be called concurrently and should not
+// block.
+type MetricsRecorder interface {
+	ObserveRequest(obs RequestObservation)
+}
+
+// WithMetrics reports every API call to recorder
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(c *Config) {
+		c.Metrics = recorder
+	}
+}
+
+// observe reports a finished call to the metrics recorder, if any
+func (c *Client) observe(method, endpoint string, status int, start time.Time, attempts int, err error) {
+	if c.metrics == nil {
+		return
+	}
+	path, _, _ := strings.Cut(endpoint, "?")
+	// No attempt is made at all if e.g. the rate limiter gives up first
+	retries := attempts - 1
+	if retries < 0 {
+		retries = 0
+	}
+	c.metrics.ObserveRequest(RequestObservation{
+		Method:     method,
+		Endpoint:   path,
+		StatusCode: status,
+		Latency:    time.Since(start),
+		Retries:    retries,
+		Err:        err,
+	})
+}
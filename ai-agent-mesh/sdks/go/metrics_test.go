@@ -0,0 +1,62 @@
+package agentmesh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects the observations it is given
+type recorder struct {
+	mu  sync.Mutex
+	obs []RequestObservation
+}
+
+func (r *recorder) ObserveRequest(obs RequestObservation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.obs = append(r.obs, obs)
+}
+
+func TestMetricsRetries(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"agent_1"}`))
+	}))
+	defer srv.Close()
+
+	t.Run("counts attempts after the first", func(t *testing.T) {
+		rec := &recorder{}
+		client := newRetryTestClient(srv.URL, WithMetrics(rec))
+		_, err := client.Agents.Get(context.Background(), "agent_1")
+
+		require.NoError(t, err)
+		require.Len(t, rec.obs, 1)
+		assert.Equal(t, 2, rec.obs[0].Retries)
+		assert.Equal(t, http.StatusOK, rec.obs[0].StatusCode)
+		assert.Equal(t, "agents/agent_1", rec.obs[0].Endpoint)
+	})
+
+	t.Run("no attempt made", func(t *testing.T) {
+		rec := &recorder{}
+		client := newRetryTestClient(srv.URL, WithMetrics(rec), WithRateLimit(1, 1))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := client.Agents.Get(ctx, "agent_1")
+
+		require.Error(t, err)
+		require.Len(t, rec.obs, 1)
+		assert.Equal(t, 0, rec.obs[0].Retries)
+		assert.Error(t, rec.obs[0].Err)
+	})
+}
@@ -1,6 +1,9 @@
 package agentmesh
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Agent represents an AI agent
 type Agent struct {
@@ -21,12 +24,45 @@ type CreateAgentRequest struct {
 	Status string                 `json:"status,omitempty"`
 }
 
-// UpdateAgentRequest is the request for updating an agent
+// UpdateAgentRequest is the request for updating an agent. Nil fields are
+// left unchanged.
 type UpdateAgentRequest struct {
 	Name   *string                 `json:"name,omitempty"`
 	Type   *string                 `json:"type,omitempty"`
 	Config *map[string]interface{} `json:"config,omitempty"`
 	Status *string                 `json:"status,omitempty"`
+
+	// ClearConfig removes the agent's config entirely by sending an
+	// explicit null. It takes precedence over Config.
+	ClearConfig bool `json:"-"`
+	// IfMatch is the ETag of the agent as last read (see
+	// ResponseMetadata.ETag), sent as If-Match so the update fails with a
+	// *ConflictError if someone else changed the agent in the meantime
+	IfMatch string `json:"-"`
+}
+
+// MarshalJSON encodes the request, sending "config": null when
+// ClearConfig is set so the server can tell clearing from omitting.
+func (r UpdateAgentRequest) MarshalJSON() ([]byte, error) {
+	type plain UpdateAgentRequest
+	if !r.ClearConfig {
+		return json.Marshal(plain(r))
+	}
+	r.Config = nil
+	return json.Marshal(struct {
+		plain
+		Config *map[string]interface{} `json:"config"`
+	}{plain: plain(r)})
+}
+
+// BatchCreateResult is the outcome of one item in a batch agent creation
+type BatchCreateResult struct {
+	// Index is the position of the item in the batch request
+	Index int
+	// Agent is the created agent, or nil if creation failed
+	Agent *Agent
+	// Err is the reason creation failed, or nil on success
+	Err error
 }
 
 // ListAgentsOptions contains options for listing agents
@@ -34,6 +70,87 @@ type ListAgentsOptions struct {
 	Status string
 	Type   string
 	Limit  int
+	// Cursor is the NextCursor from a previous page; empty for the first page
+	Cursor string
+}
+
+// SearchAgentsOptions contains the criteria for searching agents. All
+// criteria that are set must match.
+type SearchAgentsOptions struct {
+	// Name matches agents whose name contains it, case-insensitively
+	Name string
+	// Config matches agents whose config has each of the given values,
+	// keyed by dotted path, e.g. {"model": "gpt-4", "llm.provider": "openai"}
+	Config map[string]string
+	Status string
+	Type   string
+	Limit  int
+	// Cursor is the NextCursor from a previous page; empty for the first page
+	Cursor string
+}
+
+// Agent log levels, from least to most severe
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// LogEntry is a single line of an agent's runtime log
+type LogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	// Stream is "stdout" or "stderr"
+	Stream  string `json:"stream"`
+	Message string `json:"message"`
+}
+
+// LogOptions contains options for retrieving agent logs
+type LogOptions struct {
+	Start time.Time
+	End   time.Time
+	// Level keeps entries of this level and above, e.g. LogLevelWarn
+	Level string
+	// Stream keeps only "stdout" or "stderr" entries
+	Stream string
+	// Tail returns only the last Tail entries of the window
+	Tail  int
+	Limit int
+	// Cursor is the NextCursor from a previous page; empty for the first page
+	Cursor string
+}
+
+// DefaultMetricsWindow is how far back AgentService.GetMetrics aggregates
+// telemetry when no window is given
+const DefaultMetricsWindow = time.Hour
+
+// AgentMetricsOptions contains options for AgentService.GetMetrics
+type AgentMetricsOptions struct {
+	// Window is how far back telemetry is aggregated; zero means
+	// DefaultMetricsWindow
+	Window time.Duration
+}
+
+// AgentMetrics combines an agent's health with its error rate and resource
+// usage over a recent window
+type AgentMetrics struct {
+	AgentID string
+	Health  *HealthMetrics
+	// Start and End bound the window the telemetry figures cover
+	Start time.Time
+	End   time.Time
+	// Inferences and Errors count the inference and error events in the
+	// window
+	Inferences int
+	Errors     int
+	// ErrorRate is Errors as a fraction of Inferences plus Errors, or zero
+	// if there were neither
+	ErrorRate    float64
+	AvgLatencyMs float64
+	// Tokens is the total of prompt and completion tokens
+	Tokens int
+	Cost   float64
 }
 
 // Workflow represents a workflow
@@ -45,31 +162,136 @@ type Workflow struct {
 	LastExecuted   *time.Time             `json:"lastExecuted,omitempty"`
 }
 
+// ListWorkflowsOptions contains options for listing workflows
+type ListWorkflowsOptions struct {
+	AgentID string
+	// Status filters by the status of each workflow's most recent execution
+	Status WorkflowStatus
+	// Sort orders the results server-side; empty uses the server default
+	Sort WorkflowSort
+	// Descending reverses the sort order, e.g. most-executed first
+	Descending bool
+	Limit      int
+	// Cursor is the NextCursor from a previous page; empty for the first page
+	Cursor string
+}
+
+// WorkflowSort is a field workflows can be sorted by when listing
+type WorkflowSort string
+
+// Workflow sort fields
+const (
+	WorkflowSortLastExecuted   WorkflowSort = "lastExecuted"
+	WorkflowSortExecutionCount WorkflowSort = "executionCount"
+)
+
 // CreateWorkflowRequest is the request for creating a workflow
 type CreateWorkflowRequest struct {
 	AgentID    string                 `json:"agent_id"`
 	Definition map[string]interface{} `json:"definition"`
 }
 
+// WorkflowValidation is the outcome of checking a workflow definition
+type WorkflowValidation struct {
+	Valid  bool                      `json:"valid"`
+	Errors []WorkflowDefinitionError `json:"errors"`
+}
+
+// WorkflowDefinitionError is one problem found in a workflow definition
+type WorkflowDefinitionError struct {
+	// Path locates the problem in the definition, e.g. "steps[2].next"
+	Path string `json:"path"`
+	// Code classifies the problem, e.g. "unknown_node" or "missing_step"
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // WorkflowResult represents the result of a workflow execution
 type WorkflowResult struct {
 	ID        string                 `json:"id"`
-	Status    string                 `json:"status"`
+	Status    WorkflowStatus         `json:"status"`
 	Output    map[string]interface{} `json:"output"`
 	ExecutedAt time.Time             `json:"executedAt"`
 }
 
+// ExecutionHandle identifies a workflow execution started asynchronously
+type ExecutionHandle struct {
+	ID         string         `json:"id"`
+	WorkflowID string         `json:"workflowId"`
+	Status     WorkflowStatus `json:"status"`
+}
+
+// WorkflowStatus is the state of a workflow execution
+type WorkflowStatus string
+
+// Workflow execution statuses
+const (
+	WorkflowStatusPending   WorkflowStatus = "pending"
+	WorkflowStatusRunning   WorkflowStatus = "running"
+	WorkflowStatusSucceeded WorkflowStatus = "succeeded"
+	WorkflowStatusFailed    WorkflowStatus = "failed"
+	WorkflowStatusCancelled WorkflowStatus = "cancelled"
+)
+
+// IsTerminal reports whether the execution has finished and its status
+// will no longer change
+func (s WorkflowStatus) IsTerminal() bool {
+	switch s {
+	case WorkflowStatusSucceeded, WorkflowStatusFailed, WorkflowStatusCancelled:
+		return true
+	}
+	return false
+}
+
 // WorkflowExecution represents a workflow execution record
 type WorkflowExecution struct {
 	ID         string                 `json:"id"`
 	WorkflowID string                 `json:"workflowId"`
-	Status     string                 `json:"status"`
+	Status     WorkflowStatus         `json:"status"`
 	Input      map[string]interface{} `json:"input"`
 	Output     map[string]interface{} `json:"output"`
 	ExecutedAt time.Time              `json:"executedAt"`
 	Duration   int                    `json:"duration"` // milliseconds
 }
 
+// StepStatusSkipped is the status of a workflow step that didn't run,
+// e.g. because an earlier step failed or its branch wasn't taken. Steps
+// otherwise share the statuses of executions.
+const StepStatusSkipped WorkflowStatus = "skipped"
+
+// ExecutionLogs is the step-by-step record of a workflow execution
+type ExecutionLogs struct {
+	ExecutionID string         `json:"executionId"`
+	WorkflowID  string         `json:"workflowId"`
+	Status      WorkflowStatus `json:"status"`
+	// Steps are in the order they were run
+	Steps []ExecutionStep `json:"steps"`
+}
+
+// ExecutionStep records one step of a workflow execution
+type ExecutionStep struct {
+	Index  int                    `json:"index"`
+	Name   string                 `json:"name"`
+	Action string                 `json:"action"`
+	Status WorkflowStatus         `json:"status"`
+	Input  map[string]interface{} `json:"input"`
+	Output map[string]interface{} `json:"output"`
+	// Error explains why the step failed; empty unless it failed
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	Duration  int       `json:"duration"` // milliseconds
+}
+
+// FailedStep returns the first step that failed, or nil if none did
+func (l *ExecutionLogs) FailedStep() *ExecutionStep {
+	for i := range l.Steps {
+		if l.Steps[i].Status == WorkflowStatusFailed {
+			return &l.Steps[i]
+		}
+	}
+	return nil
+}
+
 // Policy represents a governance policy
 type Policy struct {
 	ID              string                 `json:"id"`
@@ -87,6 +309,28 @@ type ApplyPolicyRequest struct {
 	EnforcementMode string                 `json:"enforcement_mode"`
 }
 
+// PolicyRulesValidation is the outcome of checking the rules of a policy
+type PolicyRulesValidation struct {
+	Valid  bool              `json:"valid"`
+	Errors []PolicyRuleError `json:"errors"`
+}
+
+// PolicyRuleError is one problem found in the rules of a policy
+type PolicyRuleError struct {
+	// Path locates the problem in the rules, e.g. "mfa_required"
+	Path string `json:"path"`
+	// Code classifies the problem, e.g. RuleErrorMissing
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// UpdatePolicyRequest is the request for updating a policy
+type UpdatePolicyRequest struct {
+	Name            *string                 `json:"name,omitempty"`
+	Rules           *map[string]interface{} `json:"rules,omitempty"`
+	EnforcementMode *string                 `json:"enforcement_mode,omitempty"`
+}
+
 // ComplianceReport represents a compliance check result
 type ComplianceReport struct {
 	AgentID     string              `json:"agentId"`
@@ -95,6 +339,34 @@ type ComplianceReport struct {
 	CheckedAt   time.Time           `json:"checkedAt"`
 }
 
+// ComplianceHistoryOptions contains options for listing past compliance
+// reports
+type ComplianceHistoryOptions struct {
+	// Start and End bound when the reports were checked
+	Start time.Time
+	End   time.Time
+	// Severity keeps only reports with at least one violation of this
+	// severity
+	Severity string
+	Limit    int
+	// Cursor is the NextCursor from a previous page; empty for the first page
+	Cursor string
+}
+
+// ComplianceResult is the outcome of checking one agent's compliance
+type ComplianceResult struct {
+	// Report is the compliance report, or nil if the check failed
+	Report *ComplianceReport
+	Err    error
+}
+
+// ApplyPolicyResult is the outcome of applying a policy to one agent
+type ApplyPolicyResult struct {
+	// Policy is the applied policy, or nil if applying it failed
+	Policy *Policy
+	Err    error
+}
+
 // PolicyViolation represents a policy violation
 type PolicyViolation struct {
 	PolicyName string                 `json:"policyName"`
@@ -113,9 +385,77 @@ type TelemetryEvent struct {
 
 // TelemetryOptions contains options for querying telemetry
 type TelemetryOptions struct {
+	// Start and End bound the query window; they are sent as RFC 3339
+	Start time.Time
+	End   time.Time
+	// StartDate and EndDate are raw date strings sent as given when Start
+	// or End is zero.
+	//
+	// Deprecated: Use Start and End.
 	StartDate string
 	EndDate   string
 	EventType string
+	Limit     int
+	// Cursor is the NextCursor from a previous page; empty for the first page
+	Cursor string
+}
+
+// TelemetryTail is the result of one TelemetryService.Tail long poll
+type TelemetryTail struct {
+	// Events arrived after the cursor; empty if the poll timed out
+	Events []*TelemetryEvent `json:"events"`
+	// NextCursor is the cursor to pass to the next Tail call
+	NextCursor string `json:"nextCursor"`
+}
+
+// Telemetry aggregation functions
+const (
+	AggregateSum   = "sum"
+	AggregateAvg   = "avg"
+	AggregateP95   = "p95"
+	AggregateCount = "count"
+)
+
+// AggregateOptions contains options for a server-side telemetry rollup
+type AggregateOptions struct {
+	// Metric is the numeric payload field to aggregate, e.g. "latency_ms"
+	Metric string
+	// Function is one of AggregateSum, AggregateAvg, AggregateP95 or
+	// AggregateCount
+	Function string
+	// Interval is the bucket width; zero returns a single bucket for the
+	// whole window. It is sent in whole seconds, so it must be at least 1s.
+	Interval  time.Duration
+	Start     time.Time
+	End       time.Time
+	EventType string
+}
+
+// AggregatePoint is the aggregated value of a metric within one time bucket
+type AggregatePoint struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Value float64   `json:"value"`
+	// Count is the number of events that fell into the bucket
+	Count int `json:"count"`
+}
+
+// DeleteTelemetryOptions contains options for deleting telemetry
+type DeleteTelemetryOptions struct {
+	Start     time.Time
+	End       time.Time
+	EventType string
+	// Confirm must be true for the deletion to be sent
+	Confirm bool
+}
+
+// IngestResult is the outcome of ingesting one telemetry event
+type IngestResult struct {
+	// Index is the position of the event in the events passed to Ingest
+	Index    int
+	Accepted bool
+	// Err is the reason the event was rejected, or nil if it was accepted
+	Err error
 }
 
 // HealthMetrics represents agent health metrics
@@ -127,10 +467,74 @@ type HealthMetrics struct {
 	LastChecked  time.Time `json:"lastChecked"`
 }
 
+// HealthStatusHealthy is the health status of an agent in good health
+const HealthStatusHealthy = "healthy"
+
+// HealthResult is the outcome of fetching one agent's health metrics
+type HealthResult struct {
+	// Metrics are the agent's health metrics, or nil if fetching failed
+	Metrics *HealthMetrics
+	Err     error
+}
+
+// HealthBatch holds health metrics for several agents
+type HealthBatch struct {
+	Results map[string]*HealthResult
+	Summary HealthSummary
+}
+
+// HealthSummary aggregates the health of several agents
+type HealthSummary struct {
+	// Checked is the number of agents whose metrics were fetched
+	Checked int
+	// Failed is the number of agents whose metrics couldn't be fetched
+	Failed int
+	// Unhealthy is the number of checked agents not reporting healthy
+	Unhealthy int
+	// AverageScore is the mean health score of the checked agents
+	AverageScore float64
+}
+
+// WaitForHealthyOptions contains options for waiting on agent health
+type WaitForHealthyOptions struct {
+	// Interval between polls; DefaultPollInterval if zero
+	Interval time.Duration
+	// MaxAttempts limits the number of polls; zero polls until ctx is done
+	MaxAttempts int
+	// MinScore, if set, also accepts an agent whose health score reaches it
+	MinScore int
+}
+
+// RunOptions contains options for WorkflowService.Run
+type RunOptions struct {
+	// MaxRetries is how many times the workflow is executed again after a
+	// transient failure; zero executes it once
+	MaxRetries int
+	// PollInterval between status checks; DefaultPollInterval if zero
+	PollInterval time.Duration
+	// RetryIf reports whether a failed attempt should be retried. By
+	// default failed executions, rate limiting, 5xx responses and
+	// connection errors are retried.
+	RetryIf func(err error) bool
+}
+
+// RunResult is the outcome of WorkflowService.Run
+type RunResult struct {
+	// Result is the final result of the last execution that finished, or
+	// nil if none did
+	Result *WorkflowResult
+	// Retries is the number of executions after the first
+	Retries int
+	// AttemptErrors holds why each failed attempt failed, in order
+	AttemptErrors []error
+}
+
 // DiscoverOptions contains options for discovering agents
 type DiscoverOptions struct {
 	Capabilities []string
 	Region       string
+	Status       string
+	Type         string
 }
 
 // FederationConfig represents federation configuration
@@ -141,6 +545,15 @@ type FederationConfig struct {
 	Public       bool     `json:"public"`
 }
 
+// UpdateFederationConfigRequest is the request for updating a federated
+// agent's configuration
+type UpdateFederationConfigRequest struct {
+	// Capabilities replaces the agent's capabilities when not nil
+	Capabilities *[]string `json:"capabilities,omitempty"`
+	Region       *string   `json:"region,omitempty"`
+	Public       *bool     `json:"public,omitempty"`
+}
+
 // MarketplacePolicy represents a policy in the marketplace
 type MarketplacePolicy struct {
 	ID          string                 `json:"id"`
@@ -151,12 +564,39 @@ type MarketplacePolicy struct {
 	Rules       map[string]interface{} `json:"rules"`
 	Downloads   int                    `json:"downloads"`
 	Rating      float64                `json:"rating"`
+	Visibility  string                 `json:"visibility,omitempty"`
+}
+
+// Rating is the aggregate rating of a marketplace policy
+type Rating struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// Marketplace policy visibilities
+const (
+	VisibilityDraft  = "draft"
+	VisibilityPublic = "public"
+)
+
+// PublishPolicyRequest is the request for publishing a marketplace policy
+type PublishPolicyRequest struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Framework   string                 `json:"framework"`
+	Category    string                 `json:"category"`
+	Rules       map[string]interface{} `json:"rules"`
+	// Visibility is VisibilityDraft or VisibilityPublic; empty means draft
+	Visibility string `json:"visibility,omitempty"`
 }
 
 // MarketplaceOptions contains options for browsing marketplace
 type MarketplaceOptions struct {
 	Category  string
 	Framework string
+	Limit     int
+	// Cursor is the NextCursor from a previous page; empty for the first page
+	Cursor string
 }
 
 // Usage represents account usage metrics
@@ -167,6 +607,27 @@ type Usage struct {
 	DataTransferGB float64 `json:"dataTransferGB"`
 }
 
+// Usage series bucket sizes
+const (
+	GranularityDay  = "day"
+	GranularityWeek = "week"
+)
+
+// UsageSeriesOptions contains options for querying usage over time
+type UsageSeriesOptions struct {
+	Start time.Time
+	End   time.Time
+	// Granularity is GranularityDay or GranularityWeek; empty means daily
+	Granularity string
+}
+
+// UsagePoint is the account usage within one time bucket
+type UsagePoint struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Usage
+}
+
 // Limits represents account limits
 type Limits struct {
 	Agents                int `json:"agents"`
@@ -176,3 +637,79 @@ type Limits struct {
 	WorkflowsPerAgent     int `json:"workflowsPerAgent"`
 	APICallsRemaining     int `json:"apiCallsRemaining"`
 }
+
+// UsageProjection estimates where API call usage will end up by the end
+// of the current calendar month (UTC) at the rate seen so far this month
+type UsageProjection struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	// APICalls is the number of API calls made so far this month
+	APICalls int
+	// DailyRate is the average number of API calls per day so far
+	DailyRate float64
+	// ProjectedAPICalls is APICalls plus DailyRate over the days remaining
+	ProjectedAPICalls int
+	// Limit is the account's APICallsPerMonth, or zero if it has none
+	Limit int
+	// WillExceed reports whether ProjectedAPICalls is over Limit
+	WillExceed bool
+}
+
+// BillingHistoryOptions contains options for listing invoices
+type BillingHistoryOptions struct {
+	Start  time.Time
+	End    time.Time
+	Status string
+}
+
+// Invoice represents a billing invoice
+type Invoice struct {
+	ID          string    `json:"id"`
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Status      string    `json:"status"`
+	// LineItems is only populated by GetInvoice
+	LineItems []InvoiceLineItem `json:"lineItems,omitempty"`
+}
+
+// InvoiceLineItem is a single charge on an invoice
+type InvoiceLineItem struct {
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unitPrice"`
+	Amount      float64 `json:"amount"`
+}
+
+// MemberRole is the role of a team member in the account
+type MemberRole string
+
+// Team member roles
+const (
+	MemberRoleOwner     MemberRole = "owner"
+	MemberRoleAdmin     MemberRole = "admin"
+	MemberRoleDeveloper MemberRole = "developer"
+	MemberRoleViewer    MemberRole = "viewer"
+)
+
+// Member represents a team member of the account
+type Member struct {
+	ID    string     `json:"id"`
+	Email string     `json:"email"`
+	Role  MemberRole `json:"role"`
+	// Status is "invited" until the invitation is accepted, then "active"
+	Status    string    `json:"status"`
+	InvitedAt time.Time `json:"invitedAt"`
+}
+
+// Webhook represents a webhook subscription
+type Webhook struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs deliveries; it is only returned when the webhook is created
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
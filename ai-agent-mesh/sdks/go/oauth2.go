@@ -0,0 +1,118 @@
+package agentmesh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryMargin is how long before expiry a cached token is refreshed
+const tokenExpiryMargin = 30 * time.Second
+
+// OAuth2Config configures OAuth2 client-credentials authentication
+type OAuth2Config struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	Scopes       []string
+}
+
+// WithOAuth2 authenticates with access tokens obtained through the OAuth2
+// client-credentials grant instead of an API key. Tokens are cached and
+// refreshed shortly before they expire.
+func WithOAuth2(clientID, clientSecret, tokenURL string, scopes ...string) Option {
+	return func(c *Config) {
+		c.OAuth2 = &OAuth2Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL,
+			Scopes:       scopes,
+		}
+	}
+}
+
+// oauth2TokenSource fetches and caches client-credentials access tokens.
+// It is safe for concurrent use; concurrent callers share one refresh.
+type oauth2TokenSource struct {
+	config     OAuth2Config
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token returns a valid access token, fetching a new one if the cached
+// token is missing or about to expire
+func (s *oauth2TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Add(tokenExpiryMargin).Before(s.expires) {
+		return s.token, nil
+	}
+
+	token, expiresIn, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expires = time.Now().Add(expiresIn)
+	return s.token, nil
+}
+
+// Invalidate drops the cached token so the next call fetches a new one
+func (s *oauth2TokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+func (s *oauth2TokenSource) fetch(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", 0, &AuthenticationError{Message: fmt.Sprintf("token request failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))}
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", 0, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", 0, &AuthenticationError{Message: "token response has no access_token"}
+	}
+	expiresIn := time.Duration(tokenResp.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = time.Hour
+	}
+	return tokenResp.AccessToken, expiresIn, nil
+}
@@ -0,0 +1,181 @@
+package agentmesh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTokenServer returns a token endpoint issuing "token-1", "token-2", ...
+// valid for expiresIn seconds, and a count of the tokens it issued
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	var issued atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client-id" || secret != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+		assert.Equal(t, "agents:read agents:write", r.FormValue("scope"))
+		n := issued.Add(1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &issued
+}
+
+func newTokenSource(tokenURL, clientSecret string) *oauth2TokenSource {
+	return &oauth2TokenSource{
+		config: OAuth2Config{
+			ClientID:     "client-id",
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL,
+			Scopes:       []string{"agents:read", "agents:write"},
+		},
+		httpClient: http.DefaultClient,
+	}
+}
+
+func TestOAuth2TokenSource(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("caches the token until it is about to expire", func(t *testing.T) {
+		srv, issued := newTokenServer(t, 3600)
+		source := newTokenSource(srv.URL, "client-secret")
+
+		for i := 0; i < 3; i++ {
+			token, err := source.Token(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "token-1", token)
+		}
+		assert.EqualValues(t, 1, issued.Load())
+
+		// Move expiry inside the refresh margin
+		source.expires = time.Now().Add(tokenExpiryMargin / 2)
+		token, err := source.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "token-2", token)
+	})
+
+	t.Run("refreshes tokens that expire within the margin", func(t *testing.T) {
+		srv, issued := newTokenServer(t, 10)
+		source := newTokenSource(srv.URL, "client-secret")
+
+		first, err := source.Token(ctx)
+		require.NoError(t, err)
+		second, err := source.Token(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+		assert.EqualValues(t, 2, issued.Load())
+	})
+
+	t.Run("invalidate drops the cached token", func(t *testing.T) {
+		srv, _ := newTokenServer(t, 3600)
+		source := newTokenSource(srv.URL, "client-secret")
+
+		_, err := source.Token(ctx)
+		require.NoError(t, err)
+		source.Invalidate()
+		token, err := source.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "token-2", token)
+	})
+
+	t.Run("concurrent callers share one fetch", func(t *testing.T) {
+		srv, issued := newTokenServer(t, 3600)
+		source := newTokenSource(srv.URL, "client-secret")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				token, err := source.Token(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, "token-1", token)
+			}()
+		}
+		wg.Wait()
+		assert.EqualValues(t, 1, issued.Load())
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		srv, _ := newTokenServer(t, 3600)
+		source := newTokenSource(srv.URL, "wrong-secret")
+
+		_, err := source.Token(ctx)
+		assert.True(t, errors.Is(err, ErrUnauthorized), "got %v", err)
+		assert.Contains(t, err.Error(), "invalid_client")
+	})
+}
+
+func TestOAuth2TokenResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{"token", http.StatusOK, `{"access_token":"abc","expires_in":60}`, false},
+		{"no expiry", http.StatusOK, `{"access_token":"abc"}`, false},
+		{"no access token", http.StatusOK, `{"expires_in":60}`, true},
+		{"malformed", http.StatusOK, `{"access_token":`, true},
+		{"server error", http.StatusInternalServerError, `oops`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			token, err := newTokenSource(srv.URL, "client-secret").Token(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "abc", token)
+		})
+	}
+}
+
+func TestOAuth2Client(t *testing.T) {
+	tokenSrv, issued := newTokenServer(t, 3600)
+	var revoked atomic.Bool
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first token is revoked after its first use
+		if r.Header.Get("Authorization") == "Bearer token-1" && !revoked.CompareAndSwap(false, true) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"agent_1"}`))
+	}))
+	defer api.Close()
+
+	client := NewClient("", WithBaseURL(api.URL),
+		WithOAuth2("client-id", "client-secret", tokenSrv.URL, "agents:read", "agents:write"))
+	ctx := context.Background()
+
+	_, err := client.Agents.Get(ctx, "agent_1")
+	require.NoError(t, err)
+	_, err = client.Agents.Get(ctx, "agent_1")
+	require.True(t, errors.Is(err, ErrUnauthorized), "got %v", err)
+
+	// The 401 invalidated the cached token, so a new one is fetched
+	_, err = client.Agents.Get(ctx, "agent_1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, issued.Load())
+}
@@ -0,0 +1,64 @@
+package agentmesh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Page is a single page of results from a List-style method. Pass
+// NextCursor back in the options' Cursor field to fetch the following page.
+type Page[T any] struct {
+	Items []T
+	// TotalCount is the number of results across all pages, or zero if
+	// the endpoint doesn't report it
+	TotalCount int
+	// NextCursor is empty when there are no more pages
+	NextCursor string
+	HasMore    bool
+}
+
+// getPage retrieves one page from endpoint. The items are read from the
+// itemsKey field of the response envelope, or from the whole body when the
+// endpoint returns a bare array.
+func getPage[T any](ctx context.Context, c *Client, endpoint, itemsKey string) (*Page[T], error) {
+	page := &Page[T]{}
+	var raw json.RawMessage
+	if err := c.request(ctx, http.MethodGet, endpoint, nil, &raw); err != nil {
+		return page, err
+	}
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return page, nil
+	}
+	if raw[0] == '[' {
+		if err := c.unmarshal(raw, &page.Items); err != nil {
+			return page, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return page, nil
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return page, fmt.Errorf("failed to decode response: %w", err)
+	}
+	var meta struct {
+		TotalCount int    `json:"totalCount"`
+		NextCursor string `json:"nextCursor"`
+		HasMore    bool   `json:"hasMore"`
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return page, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if items, ok := envelope[itemsKey]; ok {
+		if err := c.unmarshal(items, &page.Items); err != nil {
+			return page, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	page.TotalCount = meta.TotalCount
+	page.NextCursor = meta.NextCursor
+	page.HasMore = meta.HasMore || meta.NextCursor != ""
+	return page, nil
+}
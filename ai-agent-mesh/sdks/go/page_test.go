@@ -0,0 +1,76 @@
+package agentmesh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPage(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Page[*Agent]
+	}{
+		{
+			name: "bare array",
+			body: `[{"id":"agent_1"},{"id":"agent_2"}]`,
+			want: Page[*Agent]{Items: []*Agent{{ID: "agent_1"}, {ID: "agent_2"}}},
+		},
+		{
+			name: "envelope with cursor",
+			body: `{"agents":[{"id":"agent_1"}],"totalCount":3,"nextCursor":"c2"}`,
+			want: Page[*Agent]{Items: []*Agent{{ID: "agent_1"}}, TotalCount: 3, NextCursor: "c2", HasMore: true},
+		},
+		{
+			name: "envelope with hasMore",
+			body: `{"agents":[{"id":"agent_1"}],"hasMore":true}`,
+			want: Page[*Agent]{Items: []*Agent{{ID: "agent_1"}}, HasMore: true},
+		},
+		{
+			name: "last page",
+			body: `{"agents":[{"id":"agent_3"}],"totalCount":3}`,
+			want: Page[*Agent]{Items: []*Agent{{ID: "agent_3"}}, TotalCount: 3},
+		},
+		{
+			name: "envelope without items",
+			body: `{"totalCount":0}`,
+			want: Page[*Agent]{},
+		},
+		{
+			name: "empty body",
+			body: ``,
+			want: Page[*Agent]{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := NewClient("test-key", WithBaseURL(srv.URL))
+			page, err := getPage[*Agent](context.Background(), client, "agents", "agents")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *page)
+		})
+	}
+
+	t.Run("strict decoding ignores the envelope", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"agents":[{"id":"agent_1"}],"nextCursor":"c2","extra":1}`))
+		}))
+		defer srv.Close()
+
+		client := NewClient("test-key", WithBaseURL(srv.URL), WithStrictDecoding())
+		page, err := getPage[*Agent](context.Background(), client, "agents", "agents")
+		require.NoError(t, err)
+		assert.Equal(t, "c2", page.NextCursor)
+		assert.Len(t, page.Items, 1)
+	})
+}
@@ -0,0 +1,88 @@
+package agentmesh
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Well-known telemetry event types
+const (
+	EventTypeInference       = "inference"
+	EventTypeError           = "error"
+	EventTypePolicyViolation = "policy_violation"
+)
+
+// ErrWrongEventType is returned by the typed payload accessors of
+// TelemetryEvent when the event is of a different type
+var ErrWrongEventType = errors.New("agentmesh: wrong telemetry event type")
+
+// InferencePayload is the payload of an EventTypeInference event
+type InferencePayload struct {
+	Model            string  `json:"model"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	LatencyMs        float64 `json:"latency_ms"`
+	Cost             float64 `json:"cost"`
+}
+
+// ErrorPayload is the payload of an EventTypeError event
+type ErrorPayload struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+}
+
+// PolicyViolationPayload is the payload of an EventTypePolicyViolation event
+type PolicyViolationPayload struct {
+	PolicyID string `json:"policy_id"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// AsInference decodes the payload of an EventTypeInference event. The raw
+// Payload map is left untouched.
+func (e *TelemetryEvent) AsInference() (*InferencePayload, error) {
+	var p InferencePayload
+	if err := e.decodePayload(EventTypeInference, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// AsError decodes the payload of an EventTypeError event. The raw Payload
+// map is left untouched.
+func (e *TelemetryEvent) AsError() (*ErrorPayload, error) {
+	var p ErrorPayload
+	if err := e.decodePayload(EventTypeError, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// AsPolicyViolation decodes the payload of an EventTypePolicyViolation
+// event. The raw Payload map is left untouched.
+func (e *TelemetryEvent) AsPolicyViolation() (*PolicyViolationPayload, error) {
+	var p PolicyViolationPayload
+	if err := e.decodePayload(EventTypePolicyViolation, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// decodePayload checks that e is of eventType and decodes its payload
+// into v
+func (e *TelemetryEvent) decodePayload(eventType string, v interface{}) error {
+	if e.EventType != eventType {
+		return fmt.Errorf("%w: event %s is %q, not %q", ErrWrongEventType, e.ID, e.EventType, eventType)
+	}
+	data, err := json.Marshal(e.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s payload: %w", eventType, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package agentmesh
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Compliance frameworks with local rule linting
+const (
+	FrameworkSOC2  = "SOC2"
+	FrameworkHIPAA = "HIPAA"
+)
+
+// Codes of the problems reported by LintPolicyRules
+const (
+	RuleErrorMissing    = "missing_rule"
+	RuleErrorUnknown    = "unknown_rule"
+	RuleErrorWrongType  = "wrong_type"
+	RuleErrorOutOfRange = "out_of_range"
+)
+
+// ruleKind is the type of value a rule takes
+type ruleKind int
+
+const (
+	ruleBool ruleKind = iota
+	ruleNumber
+	ruleString
+)
+
+func (k ruleKind) String() string {
+	switch k {
+	case ruleBool:
+		return "a boolean"
+	case ruleNumber:
+		return "a number"
+	}
+	return "a string"
+}
+
+// ruleSpec describes one rule a framework's policies may set
+type ruleSpec struct {
+	kind     ruleKind
+	required bool
+	// min and max bound a number rule; max is ignored when zero
+	min, max float64
+	// values lists the allowed values of a string rule; empty allows any
+	values []string
+}
+
+// frameworkRules are the rules checked by LintPolicyRules, by framework
+var frameworkRules = map[string]map[string]ruleSpec{
+	FrameworkSOC2: {
+		"audit_logging":      {kind: ruleBool, required: true},
+		"mfa_required":       {kind: ruleBool, required: true},
+		"encryption_at_rest": {kind: ruleBool},
+		"access_review_days": {kind: ruleNumber, min: 1, max: 365},
+		"log_retention_days": {kind: ruleNumber, min: 365},
+		"uptime_target":      {kind: ruleNumber, min: 0, max: 100},
+		"change_management":  {kind: ruleString, values: []string{"none", "review", "approval"}},
+	},
+	FrameworkHIPAA: {
+		"phi_encryption":           {kind: ruleBool, required: true},
+		"audit_logging":            {kind: ruleBool, required: true},
+		"minimum_necessary":        {kind: ruleBool},
+		"baa_required":             {kind: ruleBool},
+		"audit_retention_days":     {kind: ruleNumber, min: 2190},
+		"breach_notification_days": {kind: ruleNumber, min: 1, max: 60},
+		"phi_handling":             {kind: ruleString, values: []string{"block", "redact", "allow"}},
+	},
+}
+
+// LintPolicyRules checks the rules of a policy for framework locally,
+// without contacting the API, and returns the problems found sorted by
+// path. For FrameworkSOC2 and FrameworkHIPAA (matched case-insensitively)
+// it reports missing required rules, unknown rules, values of the wrong
+// type and numbers outside the range the framework demands; for other
+// frameworks it only checks that there are rules. It catches common
+// mistakes early but doesn't replace PolicyService.ValidateRules.
+func LintPolicyRules(framework string, rules map[string]interface{}) []PolicyRuleError {
+	if len(rules) == 0 {
+		return []PolicyRuleError{{Path: "rules", Code: RuleErrorMissing, Message: "policy has no rules"}}
+	}
+	framework = strings.ToUpper(framework)
+	specs := frameworkRules[framework]
+	if specs == nil {
+		return nil
+	}
+	normalized, err := normalizeConfig(rules)
+	if err != nil {
+		return []PolicyRuleError{{Path: "rules", Code: RuleErrorWrongType, Message: err.Error()}}
+	}
+
+	var problems []PolicyRuleError
+	for name, spec := range specs {
+		if _, ok := normalized[name]; !ok && spec.required {
+			problems = append(problems, PolicyRuleError{
+				Path:    name,
+				Code:    RuleErrorMissing,
+				Message: fmt.Sprintf("%s policies require %s", framework, name),
+			})
+		}
+	}
+	for name, value := range normalized {
+		spec, ok := specs[name]
+		if !ok {
+			problems = append(problems, PolicyRuleError{
+				Path:    name,
+				Code:    RuleErrorUnknown,
+				Message: fmt.Sprintf("%s policies have no rule %s", framework, name),
+			})
+			continue
+		}
+		if problem := spec.check(value); problem != nil {
+			problem.Path = name
+			problems = append(problems, *problem)
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Path < problems[j].Path })
+	return problems
+}
+
+// check returns the problem with a rule's JSON-decoded value, or nil. The
+// caller sets its path.
+func (spec ruleSpec) check(value interface{}) *PolicyRuleError {
+	wrongType := &PolicyRuleError{
+		Code:    RuleErrorWrongType,
+		Message: fmt.Sprintf("must be %s, not %T", spec.kind, value),
+	}
+	switch spec.kind {
+	case ruleBool:
+		if _, ok := value.(bool); !ok {
+			return wrongType
+		}
+	case ruleNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return wrongType
+		}
+		if n < spec.min || (spec.max != 0 && n > spec.max) {
+			bounds := fmt.Sprintf("at least %g", spec.min)
+			if spec.max != 0 {
+				bounds = fmt.Sprintf("between %g and %g", spec.min, spec.max)
+			}
+			return &PolicyRuleError{Code: RuleErrorOutOfRange, Message: fmt.Sprintf("must be %s, not %g", bounds, n)}
+		}
+	case ruleString:
+		s, ok := value.(string)
+		if !ok {
+			return wrongType
+		}
+		if len(spec.values) > 0 && !containsString(spec.values, s) {
+			return &PolicyRuleError{
+				Code:    RuleErrorOutOfRange,
+				Message: fmt.Sprintf("must be one of %s, not %q", strings.Join(spec.values, ", "), s),
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
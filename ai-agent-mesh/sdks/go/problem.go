@@ -0,0 +1,183 @@
+package agentmesh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem documents
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix namespaces the Type of the problems built from errors
+const problemTypePrefix = "urn:agentmesh:problem:"
+
+// ProblemDetails is an RFC 7807 problem document describing an error, for
+// services that re-emit errors from the mesh as application/problem+json
+type ProblemDetails struct {
+	// Type identifies the kind of problem, e.g.
+	// "urn:agentmesh:problem:not-found"
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	// Instance is the request ID the API assigned to the failed request
+	Instance string `json:"instance,omitempty"`
+	// Code is the API's machine-readable error code, if it sent one
+	Code string `json:"code,omitempty"`
+	// Errors maps each invalid field to what is wrong with it
+	Errors map[string]string `json:"errors,omitempty"`
+	// RetryAfter is how many seconds to wait before retrying
+	RetryAfter int `json:"retryAfter,omitempty"`
+}
+
+// WriteResponse writes the problem as an application/problem+json
+// response with its status code, or 500 if it has none
+func (p *ProblemDetails) WriteResponse(w http.ResponseWriter) error {
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// Problem converts the error to an RFC 7807 problem document
+func (e *APIError) Problem() *ProblemDetails {
+	return &ProblemDetails{
+		Type:     problemTypePrefix + "api-error",
+		Title:    http.StatusText(e.StatusCode),
+		Status:   e.StatusCode,
+		Detail:   e.Message,
+		Instance: e.RequestID,
+		Code:     e.Code,
+	}
+}
+
+// Problem converts the error to an RFC 7807 problem document
+func (e *AuthenticationError) Problem() *ProblemDetails {
+	return &ProblemDetails{
+		Type:     problemTypePrefix + "unauthorized",
+		Title:    "Unauthorized",
+		Status:   http.StatusUnauthorized,
+		Detail:   e.Message,
+		Instance: e.RequestID,
+	}
+}
+
+// Problem converts the error to an RFC 7807 problem document
+func (e *NotFoundError) Problem() *ProblemDetails {
+	return &ProblemDetails{
+		Type:     problemTypePrefix + "not-found",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   e.Message,
+		Instance: e.RequestID,
+	}
+}
+
+// Problem converts the error to an RFC 7807 problem document
+func (e *RateLimitError) Problem() *ProblemDetails {
+	return &ProblemDetails{
+		Type:       problemTypePrefix + "rate-limited",
+		Title:      "Too Many Requests",
+		Status:     http.StatusTooManyRequests,
+		Detail:     e.Message,
+		Instance:   e.RequestID,
+		RetryAfter: int(math.Ceil(e.RetryAfter.Seconds())),
+	}
+}
+
+// Problem converts the error to an RFC 7807 problem document. Validation
+// errors detected client-side are reported as 400 and have no request ID.
+func (e *ValidationError) Problem() *ProblemDetails {
+	return &ProblemDetails{
+		Type:     problemTypePrefix + "validation",
+		Title:    "Validation Failed",
+		Status:   e.status(),
+		Detail:   e.Message,
+		Instance: e.RequestID,
+		Errors:   e.Fields,
+	}
+}
+
+// Problem converts the error to an RFC 7807 problem document
+func (e *ConflictError) Problem() *ProblemDetails {
+	return &ProblemDetails{
+		Type:     problemTypePrefix + "conflict",
+		Title:    "Precondition Failed",
+		Status:   http.StatusPreconditionFailed,
+		Detail:   e.Message,
+		Instance: e.RequestID,
+	}
+}
+
+// Problem converts the error to an RFC 7807 problem document
+func (e *ServerError) Problem() *ProblemDetails {
+	return &ProblemDetails{
+		Type:     problemTypePrefix + "server-error",
+		Title:    http.StatusText(e.StatusCode),
+		Status:   e.StatusCode,
+		Detail:   e.Message,
+		Instance: e.RequestID,
+		Code:     e.Code,
+	}
+}
+
+// ProblemFromError converts any error returned by the client to an RFC
+// 7807 problem document, looking through wrappers such as *RetryError.
+// Errors that didn't come from the API are reported as 503 if the circuit
+// breaker is open, 504 if the context deadline passed, and otherwise as
+// 502, since the API couldn't be reached.
+func ProblemFromError(err error) *ProblemDetails {
+	var (
+		authErr       *AuthenticationError
+		notFoundErr   *NotFoundError
+		rateLimitErr  *RateLimitError
+		validationErr *ValidationError
+		conflictErr   *ConflictError
+		serverErr     *ServerError
+		apiErr        *APIError
+	)
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &authErr):
+		return authErr.Problem()
+	case errors.As(err, &notFoundErr):
+		return notFoundErr.Problem()
+	case errors.As(err, &rateLimitErr):
+		return rateLimitErr.Problem()
+	case errors.As(err, &validationErr):
+		return validationErr.Problem()
+	case errors.As(err, &conflictErr):
+		return conflictErr.Problem()
+	case errors.As(err, &serverErr):
+		return serverErr.Problem()
+	case errors.As(err, &apiErr):
+		return apiErr.Problem()
+	case errors.Is(err, ErrCircuitOpen):
+		return &ProblemDetails{
+			Type:   problemTypePrefix + "unavailable",
+			Title:  "Service Unavailable",
+			Status: http.StatusServiceUnavailable,
+			Detail: err.Error(),
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &ProblemDetails{
+			Type:   problemTypePrefix + "timeout",
+			Title:  "Gateway Timeout",
+			Status: http.StatusGatewayTimeout,
+			Detail: err.Error(),
+		}
+	}
+	return &ProblemDetails{
+		Type:   problemTypePrefix + "unreachable",
+		Title:  "Bad Gateway",
+		Status: http.StatusBadGateway,
+		Detail: err.Error(),
+	}
+}
@@ -0,0 +1,98 @@
+package agentmesh
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// redactedValue replaces secret config values
+const redactedValue = "REDACTED"
+
+// secretKeySuffixes mark config keys holding secrets: a key is secret if,
+// lowercased and with "_", "-" and "." removed, it ends with one of them.
+// Matching suffixes rather than substrings keeps e.g. "max_tokens".
+var secretKeySuffixes = []string{
+	"apikey",
+	"accesskey",
+	"secretkey",
+	"privatekey",
+	"secret",
+	"token",
+	"password",
+	"passwd",
+	"credential",
+	"credentials",
+	"authorization",
+}
+
+// RedactSecrets returns a deep copy of the agent whose config values under
+// secret-looking keys, such as "api_key", "client_secret" or
+// "access_token", are replaced by "REDACTED" at any depth. Use it before
+// logging or printing an agent; the original is left untouched.
+func (a *Agent) RedactSecrets() *Agent {
+	clone := a.Clone()
+	if clone != nil {
+		redactMap(clone.Config)
+	}
+	return clone
+}
+
+// LogValue logs the agent with its secrets redacted, so an agent passed to
+// a slog logger never leaks them
+func (a *Agent) LogValue() slog.Value {
+	if a == nil {
+		return slog.AnyValue(nil)
+	}
+	redacted := a.RedactSecrets()
+	return slog.GroupValue(
+		slog.String("id", redacted.ID),
+		slog.String("name", redacted.Name),
+		slog.String("type", redacted.Type),
+		slog.String("status", redacted.Status),
+		slog.Any("config", redacted.Config),
+	)
+}
+
+// redactMap replaces the secret values of m in place, recursing into
+// nested maps and slices
+func redactMap(m map[string]interface{}) {
+	for key, value := range m {
+		if isSecretKey(key) {
+			m[key] = redactedValue
+			continue
+		}
+		redactValue(value)
+	}
+}
+
+func redactValue(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		redactMap(v)
+	case []interface{}:
+		for _, item := range v {
+			redactValue(item)
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			redactMap(item)
+		}
+	case map[string]string:
+		for key := range v {
+			if isSecretKey(key) {
+				v[key] = redactedValue
+			}
+		}
+	}
+}
+
+// isSecretKey reports whether a config key names a secret
+func isSecretKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
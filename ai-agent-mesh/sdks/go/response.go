@@ -0,0 +1,87 @@
+package agentmesh
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// requestIDHeader carries the server-assigned ID of each request
+const requestIDHeader = "X-Request-ID"
+
+// ResponseMetadata holds details of an API response that aren't part of
+// the decoded result
+type ResponseMetadata struct {
+	// RequestID is the server-assigned request ID to quote to support
+	RequestID string
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// ETag identifies the version of the returned resource; pass it as
+	// UpdateAgentRequest.IfMatch for optimistic concurrency
+	ETag string
+	// Header holds all response headers, e.g. Location after a create
+	Header http.Header
+}
+
+type responseMetadataKey struct{}
+
+// WithResponseMetadata returns a context that makes the client record
+// metadata from the response into md. When a call is retried md reflects
+// the last attempt.
+//
+//	var md agentmesh.ResponseMetadata
+//	agent, err := client.Agents.Get(agentmesh.WithResponseMetadata(ctx, &md), "agent_123")
+//	log.Printf("request ID: %s, status: %d", md.RequestID, md.StatusCode)
+func WithResponseMetadata(ctx context.Context, md *ResponseMetadata) context.Context {
+	return context.WithValue(ctx, responseMetadataKey{}, md)
+}
+
+func responseMetadataFrom(ctx context.Context) *ResponseMetadata {
+	md, _ := ctx.Value(responseMetadataKey{}).(*ResponseMetadata)
+	return md
+}
+
+// RateLimit is a snapshot of the account's API rate limit as reported by
+// the X-RateLimit-* response headers
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends
+	Reset time.Time
+}
+
+// LastRateLimit returns the rate limit reported by the most recent
+// response that carried rate-limit headers, and false if none has yet
+func (c *Client) LastRateLimit() (RateLimit, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rateLimit == nil {
+		return RateLimit{}, false
+	}
+	return *c.rateLimit, true
+}
+
+// recordRateLimit stores the rate-limit headers of resp, if present
+func (c *Client) recordRateLimit(resp *http.Response) {
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	rl := &RateLimit{Limit: limit, Remaining: remaining}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		// Small values are a delay in seconds rather than a Unix timestamp
+		if reset < 1e9 {
+			rl.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+		} else {
+			rl.Reset = time.Unix(reset, 0)
+		}
+	}
+
+	c.mu.Lock()
+	c.rateLimit = rl
+	c.mu.Unlock()
+}
@@ -0,0 +1,134 @@
+package agentmesh
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Default backoff parameters
+const (
+	DefaultBackoffInitial    = 500 * time.Millisecond
+	DefaultBackoffMax        = 30 * time.Second
+	DefaultBackoffMultiplier = 2.0
+)
+
+// backoffPolicy computes the delays between retry attempts
+type backoffPolicy struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     bool
+}
+
+// WithBackoff sets the retry backoff: the first retry waits initial, each
+// further retry multiplier times longer, up to max. With jitter each delay
+// is instead drawn uniformly between zero and that value, which spreads
+// out retries from many clients. A max of zero or less means
+// DefaultBackoffMax, an initial delay of zero or less retries immediately,
+// and a multiplier below 1 is treated as 1.
+func WithBackoff(initial, max time.Duration, multiplier float64, jitter bool) Option {
+	return func(c *Config) {
+		c.BackoffInitial = initial
+		c.BackoffMax = max
+		c.BackoffMultiplier = multiplier
+		c.BackoffJitter = jitter
+	}
+}
+
+// WithRetryBudget caps the total time a call may spend on retries: no
+// retry is started, or waited for, past maxTotal after the first attempt
+// began, even if retries remain. Zero means no cap.
+func WithRetryBudget(maxTotal time.Duration) Option {
+	return func(c *Config) {
+		c.RetryBudget = maxTotal
+	}
+}
+
+// isRetryableStatus reports whether a response status indicates a
+// transient failure worth retrying
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isIdempotent reports whether a request can be replayed after the server
+// has responded. Non-idempotent methods qualify only when they carry an
+// idempotency key.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// delay returns the wait before retry number attempt+1
+func (b backoffPolicy) delay(attempt int) time.Duration {
+	max := b.max
+	if max <= 0 {
+		max = DefaultBackoffMax
+	}
+	multiplier := b.multiplier
+	if multiplier < 1 || math.IsNaN(multiplier) {
+		multiplier = 1
+	}
+	d := float64(b.initial) * math.Pow(multiplier, float64(attempt))
+	if d > float64(max) || math.IsInf(d, 0) || math.IsNaN(d) {
+		d = float64(max)
+	}
+	// Below 1ns there is nothing to jitter, and rand.Int63n panics on 0
+	if int64(d) < 1 {
+		return 0
+	}
+	if b.jitter {
+		return time.Duration(rand.Int63n(int64(d)) + 1)
+	}
+	return time.Duration(d)
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first. It
+// returns ctx.Err() without waiting if ctx is already done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of
+// seconds or as an HTTP-date. It returns zero if the value is absent or
+// malformed.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
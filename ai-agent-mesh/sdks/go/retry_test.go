@@ -0,0 +1,46 @@
+package agentmesh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff backoffPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{"first retry waits initial", backoffPolicy{initial: time.Second, max: time.Minute, multiplier: 2}, 0, time.Second},
+		{"grows by multiplier", backoffPolicy{initial: time.Second, max: time.Minute, multiplier: 2}, 3, 8 * time.Second},
+		{"capped at max", backoffPolicy{initial: time.Second, max: 5 * time.Second, multiplier: 2}, 10, 5 * time.Second},
+		{"zero max means default max", backoffPolicy{initial: time.Second, multiplier: 2}, 10, DefaultBackoffMax},
+		{"zero max keeps small delays", backoffPolicy{initial: time.Second, multiplier: 2}, 1, 2 * time.Second},
+		{"multiplier below 1 doesn't shrink delays", backoffPolicy{initial: time.Second, max: time.Minute, multiplier: 0.1}, 3, time.Second},
+		{"zero initial retries immediately", backoffPolicy{max: time.Minute, multiplier: 2}, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.backoff.delay(tt.attempt))
+		})
+	}
+
+	t.Run("jitter stays within the delay", func(t *testing.T) {
+		b := backoffPolicy{initial: time.Second, max: time.Minute, multiplier: 2, jitter: true}
+		for i := 0; i < 100; i++ {
+			d := b.delay(2)
+			assert.Greater(t, d, time.Duration(0))
+			assert.LessOrEqual(t, d, 4*time.Second)
+		}
+	})
+
+	t.Run("sub-nanosecond delay with jitter", func(t *testing.T) {
+		b := backoffPolicy{initial: time.Nanosecond, max: time.Second, multiplier: 0.5, jitter: true}
+		for attempt := 0; attempt < 5; attempt++ {
+			assert.NotPanics(t, func() { b.delay(attempt) })
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package agentmesh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Run executes a workflow, waits for it to finish and returns its result,
+// executing it again after transient failures up to opts.MaxRetries times
+// with the client's backoff in between. An execution that ends failed or
+// cancelled counts as a failure matching ErrWorkflowFailed; cancelled
+// executions are never retried.
+//
+// The returned RunResult is never nil and records every attempt, also when
+// an error is returned; the error is that of the last attempt.
+func (s *WorkflowService) Run(ctx context.Context, workflowID string, input map[string]interface{}, opts *RunOptions) (*RunResult, error) {
+	o := RunOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.RetryIf == nil {
+		o.RetryIf = isTransient
+	}
+
+	run := &RunResult{}
+	for attempt := 0; ; attempt++ {
+		run.Retries = attempt
+		result, err := s.runOnce(ctx, workflowID, input, o)
+		if result != nil {
+			run.Result = result
+		}
+		if err == nil {
+			return run, nil
+		}
+		run.AttemptErrors = append(run.AttemptErrors, err)
+
+		if ctx.Err() != nil || attempt >= o.MaxRetries || !o.RetryIf(err) ||
+			(result != nil && result.Status == WorkflowStatusCancelled) {
+			return run, err
+		}
+		if err := sleepContext(ctx, s.client.backoff.delay(attempt)); err != nil {
+			return run, err
+		}
+	}
+}
+
+// runOnce executes the workflow once and waits for the execution to finish
+func (s *WorkflowService) runOnce(ctx context.Context, workflowID string, input map[string]interface{}, o RunOptions) (*WorkflowResult, error) {
+	handle, err := s.ExecuteAsync(ctx, workflowID, input)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.WaitForCompletion(ctx, handle.ID, o.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status != WorkflowStatusSucceeded {
+		return result, fmt.Errorf("execution %s %s: %w", handle.ID, result.Status, ErrWorkflowFailed)
+	}
+	return result, nil
+}
+
+// isTransient reports whether a failed attempt is worth retrying: failed
+// executions and everything but context errors, validation errors and 4xx
+// responses other than 429
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrValidation) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
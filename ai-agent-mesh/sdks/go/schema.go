@@ -0,0 +1,187 @@
+package agentmesh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ConfigSchema is the subset of JSON Schema used to describe agent
+// configs: type, properties, required, additionalProperties, items, enum,
+// minimum/maximum and minLength/maxLength
+type ConfigSchema struct {
+	Type                 string                   `json:"type,omitempty"`
+	Properties           map[string]*ConfigSchema `json:"properties,omitempty"`
+	Required             []string                 `json:"required,omitempty"`
+	AdditionalProperties *bool                    `json:"additionalProperties,omitempty"`
+	Items                *ConfigSchema            `json:"items,omitempty"`
+	Enum                 []interface{}            `json:"enum,omitempty"`
+	Minimum              *float64                 `json:"minimum,omitempty"`
+	Maximum              *float64                 `json:"maximum,omitempty"`
+	MinLength            *int                     `json:"minLength,omitempty"`
+	MaxLength            *int                     `json:"maxLength,omitempty"`
+}
+
+// WithConfigSchemaValidation makes AgentService.Create validate configs
+// against the schema of the agent type before sending them
+func WithConfigSchemaValidation() Option {
+	return func(c *Config) {
+		c.ConfigSchemaValidation = true
+	}
+}
+
+// schemaCache holds config schemas by agent type
+type schemaCache struct {
+	mu      sync.Mutex
+	schemas map[string]*ConfigSchema
+}
+
+// GetConfigSchema retrieves the config schema of an agent type. Schemas
+// are cached for the life of the client.
+func (s *AgentService) GetConfigSchema(ctx context.Context, agentType string) (*ConfigSchema, error) {
+	cache := &s.client.schemas
+	cache.mu.Lock()
+	schema, ok := cache.schemas[agentType]
+	cache.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	endpoint := fmt.Sprintf("agent-types/%s/schema", url.PathEscape(agentType))
+	var raw json.RawMessage
+	if err := s.client.request(ctx, http.MethodGet, endpoint, nil, &raw); err != nil {
+		return nil, err
+	}
+	// Schemas are decoded without the client's decoding options: they carry
+	// keywords ConfigSchema doesn't model, such as title and description, and
+	// enum values must stay float64 to compare equal to validated configs
+	schema = &ConfigSchema{}
+	if err := json.Unmarshal(raw, schema); err != nil {
+		return nil, fmt.Errorf("failed to decode config schema: %w", err)
+	}
+
+	cache.mu.Lock()
+	if cache.schemas == nil {
+		cache.schemas = map[string]*ConfigSchema{}
+	}
+	cache.schemas[agentType] = schema
+	cache.mu.Unlock()
+	return schema, nil
+}
+
+// ValidateConfig checks config against the schema of agentType. Violations
+// are returned as a *ValidationError keyed by field path, e.g.
+// "config.temperature".
+func (s *AgentService) ValidateConfig(ctx context.Context, agentType string, config map[string]interface{}) error {
+	schema, err := s.GetConfigSchema(ctx, agentType)
+	if err != nil {
+		return err
+	}
+	return schema.Validate(config)
+}
+
+// Validate checks config against the schema
+func (schema *ConfigSchema) Validate(config map[string]interface{}) error {
+	// Round-trip through JSON so values have the types the schema describes
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	f := fieldErrors{}
+	schema.check("config", value, f)
+	return f.err("agent config")
+}
+
+// check records every violation of value against the schema in f
+func (schema *ConfigSchema) check(path string, value interface{}, f fieldErrors) {
+	if schema.Type != "" && !hasSchemaType(value, schema.Type) {
+		f[path] = fmt.Sprintf("must be of type %s", schema.Type)
+		return
+	}
+	if len(schema.Enum) > 0 && !inEnum(value, schema.Enum) {
+		f[path] = fmt.Sprintf("must be one of %v", schema.Enum)
+		return
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if schema.Minimum != nil && v < *schema.Minimum {
+			f[path] = fmt.Sprintf("must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && v > *schema.Maximum {
+			f[path] = fmt.Sprintf("must be at most %v", *schema.Maximum)
+		}
+	case string:
+		if schema.MinLength != nil && len([]rune(v)) < *schema.MinLength {
+			f[path] = fmt.Sprintf("must be at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && len([]rune(v)) > *schema.MaxLength {
+			f[path] = fmt.Sprintf("must be at most %d characters", *schema.MaxLength)
+		}
+	case []interface{}:
+		if schema.Items != nil {
+			for i, item := range v {
+				schema.Items.check(fmt.Sprintf("%s[%d]", path, i), item, f)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				f[path+"."+name] = "is required"
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			prop, ok := schema.Properties[key]
+			switch {
+			case ok:
+				prop.check(path+"."+key, v[key], f)
+			case schema.AdditionalProperties != nil && !*schema.AdditionalProperties:
+				f[path+"."+key] = "is not allowed"
+			}
+		}
+	}
+}
+
+// hasSchemaType reports whether a decoded JSON value has the JSON Schema
+// type typ
+func hasSchemaType(value interface{}, typ string) bool {
+	switch v := value.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case float64:
+		return typ == "number" || (typ == "integer" && v == float64(int64(v)))
+	case string:
+		return typ == "string"
+	case []interface{}:
+		return typ == "array"
+	case map[string]interface{}:
+		return typ == "object"
+	}
+	return false
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package agentmesh
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSchema uses keywords ConfigSchema doesn't model alongside a numeric
+// enum, as real schemas do
+const testSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "LLM agent config",
+	"description": "Config of llm agents",
+	"type": "object",
+	"required": ["model"],
+	"properties": {
+		"model": {"type": "string", "enum": ["gpt-4", "gpt-3.5"], "default": "gpt-4"},
+		"maxTokens": {"type": "number", "enum": [256, 1024, 4096]}
+	}
+}`
+
+func TestValidateConfigDecodingOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/agent-types/llm/schema", r.URL.Path)
+		w.Write([]byte(testSchema))
+	}))
+	defer srv.Close()
+
+	options := []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"strict decoding", []Option{WithStrictDecoding()}},
+		{"use number", []Option{WithUseNumber()}},
+		{"both", []Option{WithStrictDecoding(), WithUseNumber()}},
+	}
+	configs := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"model": "gpt-4", "maxTokens": 1024}, ""},
+		{"int enum value", map[string]interface{}{"model": "gpt-4", "maxTokens": int64(4096)}, ""},
+		{"not in numeric enum", map[string]interface{}{"model": "gpt-4", "maxTokens": 512}, "config.maxTokens"},
+		{"not in string enum", map[string]interface{}{"model": "claude"}, "config.model"},
+		{"missing required", map[string]interface{}{}, "config.model"},
+	}
+	for _, opt := range options {
+		t.Run(opt.name, func(t *testing.T) {
+			client := NewClient("test-key", append([]Option{WithBaseURL(srv.URL)}, opt.opts...)...)
+			for _, tt := range configs {
+				t.Run(tt.name, func(t *testing.T) {
+					err := client.Agents.ValidateConfig(context.Background(), "llm", tt.config)
+					if tt.wantErr == "" {
+						assert.NoError(t, err)
+						return
+					}
+					var validationErr *ValidationError
+					require.True(t, errors.As(err, &validationErr), "got %v", err)
+					assert.Contains(t, validationErr.Fields, tt.wantErr)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package agentmesh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AgentSpec is the portable definition of an agent, for keeping agents in
+// version-controlled YAML or JSON files and applying them with
+// AgentService.Apply. Agents are identified by name.
+type AgentSpec struct {
+	Name   string                 `json:"name" yaml:"name"`
+	Type   string                 `json:"type" yaml:"type"`
+	Config map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+	Status string                 `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// SpecFromAgent returns the spec of an existing agent, leaving out
+// server-assigned fields such as its ID and timestamps. It returns nil for
+// a nil agent.
+func SpecFromAgent(agent *Agent) *AgentSpec {
+	if agent == nil {
+		return nil
+	}
+	clone := agent.Clone()
+	return &AgentSpec{
+		Name:   clone.Name,
+		Type:   clone.Type,
+		Config: clone.Config,
+		Status: clone.Status,
+	}
+}
+
+// ParseAgentSpec parses a spec from YAML or JSON. Config values are
+// normalized to the types the API returns, numbers becoming float64, so
+// a spec compares equal to the agent it describes whichever format it was
+// written in.
+func ParseAgentSpec(data []byte) (*AgentSpec, error) {
+	var spec AgentSpec
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &spec); err != nil {
+			return nil, fmt.Errorf("failed to parse agent spec: %w", err)
+		}
+		return &spec, nil
+	}
+
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse agent spec: %w", err)
+	}
+	config, err := normalizeConfig(spec.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent spec: %w", err)
+	}
+	spec.Config = config
+	return &spec, nil
+}
+
+// YAML encodes the spec as YAML with config keys sorted, so equal specs
+// encode identically
+func (s *AgentSpec) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(s); err != nil {
+		return nil, fmt.Errorf("failed to encode agent spec: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode agent spec: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// JSON encodes the spec as indented JSON with config keys sorted, so equal
+// specs encode identically
+func (s *AgentSpec) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode agent spec: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Request returns the request that creates the agent described by the spec
+func (s *AgentSpec) Request() *CreateAgentRequest {
+	return &CreateAgentRequest{
+		Name:   s.Name,
+		Type:   s.Type,
+		Config: copyMap(s.Config),
+		Status: s.Status,
+	}
+}
+
+// Apply makes the agent named spec.Name match spec: it is created if no
+// agent has that name, and otherwise updated in place, with its config
+// replaced by spec.Config. It fails with a *ValidationError if several
+// agents share the name.
+func (s *AgentService) Apply(ctx context.Context, spec *AgentSpec) (*Agent, error) {
+	if spec == nil {
+		return nil, &ValidationError{Message: "agent spec is nil"}
+	}
+	req := spec.Request()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findByName(ctx, spec.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return s.Create(ctx, req)
+	}
+
+	if s.client.validateConfigs {
+		if err := s.ValidateConfig(ctx, req.Type, req.Config); err != nil {
+			return nil, err
+		}
+	}
+	config := req.Config
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	update := &UpdateAgentRequest{Type: &req.Type, Config: &config}
+	if req.Status != "" {
+		update.Status = &req.Status
+	}
+	return s.Update(ctx, existing.ID, update)
+}
+
+// findByName returns the agent with exactly the given name, or nil if
+// there is none
+func (s *AgentService) findByName(ctx context.Context, name string) (*Agent, error) {
+	var found *Agent
+	opts := &SearchAgentsOptions{Name: name}
+	for {
+		page, err := s.Search(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, agent := range page.Items {
+			if agent.Name != name {
+				continue
+			}
+			if found != nil {
+				return nil, &ValidationError{
+					Message: fmt.Sprintf("several agents are named %q", name),
+					Fields:  map[string]string{"name": "is ambiguous"},
+				}
+			}
+			found = agent
+		}
+		if page.NextCursor == "" {
+			return found, nil
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
+
+// normalizeConfig round-trips config through JSON so its values have the
+// same types as a config decoded from an API response
+func normalizeConfig(config map[string]interface{}) (map[string]interface{}, error) {
+	if config == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
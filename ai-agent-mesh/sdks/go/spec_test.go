@@ -0,0 +1,116 @@
+package agentmesh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentSpecRoundTrip(t *testing.T) {
+	agent := &Agent{
+		ID:     "agent_1",
+		Name:   "support-bot",
+		Type:   "llm",
+		Status: "active",
+		Config: map[string]interface{}{
+			"model":       "gpt-4",
+			"temperature": 0.7,
+			"maxTokens":   float64(1024),
+			"tools": []interface{}{
+				map[string]interface{}{"name": "search", "enabled": true},
+				"calculator",
+			},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	encoders := []struct {
+		name   string
+		encode func(*AgentSpec) ([]byte, error)
+	}{
+		{"yaml", (*AgentSpec).YAML},
+		{"json", (*AgentSpec).JSON},
+	}
+	for _, enc := range encoders {
+		t.Run(enc.name, func(t *testing.T) {
+			data, err := enc.encode(SpecFromAgent(agent))
+			require.NoError(t, err)
+			spec, err := ParseAgentSpec(data)
+			require.NoError(t, err)
+
+			changes, err := DiffAgent(agent, spec)
+			require.NoError(t, err)
+			assert.Empty(t, changes)
+
+			again, err := enc.encode(spec)
+			require.NoError(t, err)
+			assert.Equal(t, string(data), string(again))
+		})
+	}
+
+	t.Run("spec doesn't share config with the agent", func(t *testing.T) {
+		spec := SpecFromAgent(agent)
+		spec.Config["model"] = "gpt-3.5"
+		assert.Equal(t, "gpt-4", agent.Config["model"])
+	})
+
+	t.Run("nil agent", func(t *testing.T) {
+		assert.Nil(t, SpecFromAgent(nil))
+	})
+}
+
+func TestDiffAgent(t *testing.T) {
+	current := &Agent{
+		Name:   "support-bot",
+		Type:   "llm",
+		Status: "active",
+		Config: map[string]interface{}{"model": "gpt-4", "temperature": 0.7},
+	}
+	tests := []struct {
+		name    string
+		current *Agent
+		desired *AgentSpec
+		want    []string
+	}{
+		{
+			name:    "matching",
+			current: current,
+			desired: &AgentSpec{Name: "support-bot", Type: "llm", Config: map[string]interface{}{"model": "gpt-4", "temperature": 0.7}},
+		},
+		{
+			name:    "int equals float",
+			current: &Agent{Name: "a", Config: map[string]interface{}{"n": float64(3)}},
+			desired: &AgentSpec{Name: "a", Config: map[string]interface{}{"n": 3}},
+		},
+		{
+			name:    "modified, added and removed",
+			current: current,
+			desired: &AgentSpec{Name: "support-bot", Type: "llm", Status: "paused", Config: map[string]interface{}{"model": "gpt-4", "topP": 0.9}},
+			want: []string{
+				"- config.temperature: 0.7",
+				"+ config.topP: 0.9",
+				"~ status: active => paused",
+			},
+		},
+		{
+			name:    "missing agent",
+			current: nil,
+			desired: &AgentSpec{Name: "a", Type: "llm"},
+			want:    []string{"+ name: a", "+ type: llm"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := DiffAgent(tt.current, tt.desired)
+			require.NoError(t, err)
+			var got []string
+			for _, c := range changes {
+				got = append(got, c.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
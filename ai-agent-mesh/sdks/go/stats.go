@@ -0,0 +1,104 @@
+package agentmesh
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the client's request counters since it was
+// created, for logging SDK health when no MetricsRecorder is wired up.
+// Streams opened with Stream, StreamOutput and Tail are not counted.
+type Stats struct {
+	// Requests is the number of API calls, each counted once however many
+	// times it was retried
+	Requests int64
+	// Retries is the number of attempts made after the first, across all
+	// calls
+	Retries int64
+	// Errors is the number of calls that returned an error
+	Errors int64
+	// ErrorsByType breaks Errors down into "authentication", "not_found",
+	// "rate_limit", "validation", "conflict", "server", "api",
+	// "circuit_open", "context" and "other"
+	ErrorsByType map[string]int64
+	// RateLimitHits is the number of attempts rejected with 429 Too Many
+	// Requests, including ones that were then retried successfully
+	RateLimitHits int64
+}
+
+// Stats returns a snapshot of the client's request counters
+func (c *Client) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// clientStats holds the counters behind Stats. The zero value is ready to
+// use.
+type clientStats struct {
+	requests      atomic.Int64
+	retries       atomic.Int64
+	rateLimitHits atomic.Int64
+
+	mu     sync.Mutex
+	errors map[string]int64
+}
+
+// record counts a finished call
+func (s *clientStats) record(attempts int, err error) {
+	s.requests.Add(1)
+	if attempts > 1 {
+		s.retries.Add(int64(attempts - 1))
+	}
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.errors == nil {
+		s.errors = map[string]int64{}
+	}
+	s.errors[errorType(err)]++
+}
+
+func (s *clientStats) snapshot() Stats {
+	stats := Stats{
+		Requests:      s.requests.Load(),
+		Retries:       s.retries.Load(),
+		RateLimitHits: s.rateLimitHits.Load(),
+		ErrorsByType:  map[string]int64{},
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for kind, n := range s.errors {
+		stats.ErrorsByType[kind] = n
+		stats.Errors += n
+	}
+	return stats
+}
+
+// errorType classifies err for Stats.ErrorsByType
+func errorType(err error) string {
+	var apiErr *APIError
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		return "authentication"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limit"
+	case errors.Is(err, ErrValidation):
+		return "validation"
+	case errors.Is(err, ErrConflict):
+		return "conflict"
+	case errors.Is(err, ErrServer):
+		return "server"
+	case errors.As(err, &apiErr):
+		return "api"
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "context"
+	}
+	return "other"
+}
@@ -0,0 +1,221 @@
+package agentmesh
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Stream opens a Server-Sent Events connection and delivers telemetry
+// events for an agent as they occur. Only opts.EventType is used.
+//
+// Dropped connections are re-established with backoff, resuming from the
+// last received event. Events that can't be decoded are skipped, with a
+// warning to the client's logger if there is one. A non-retryable failure,
+// such as ErrCircuitOpen, is sent on the error channel. Both channels are
+// closed when ctx is cancelled or the stream fails permanently.
+func (s *TelemetryService) Stream(ctx context.Context, agentID string, opts *TelemetryOptions) (<-chan *TelemetryEvent, <-chan error) {
+	events := make(chan *TelemetryEvent)
+	errs := make(chan error, 1)
+
+	query := url.Values{}
+	if opts != nil && opts.EventType != "" {
+		query.Set("event_type", opts.EventType)
+	}
+	endpoint := withQuery(fmt.Sprintf("agents/%s/telemetry/stream", agentID), query)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		var lastEventID string
+		for attempt := 0; ; attempt++ {
+			connected, retry, err := s.client.stream(ctx, endpoint, &lastEventID, events)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil && !retry {
+				errs <- err
+				return
+			}
+			if connected {
+				attempt = 0
+			}
+			if sleepContext(ctx, s.client.backoff.delay(attempt)) != nil {
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// Tail long-polls for an agent's telemetry events after sinceCursor,
+// blocking until some arrive or the server's poll timeout passes, and
+// returns them with the cursor for the next call. An empty sinceCursor
+// starts from now. It is an alternative to Stream for networks whose
+// proxies block Server-Sent Events:
+//
+//	cursor := ""
+//	for {
+//		tail, err := client.Telemetry.Tail(ctx, agentID, cursor)
+//		if err != nil {
+//			return err
+//		}
+//		for _, event := range tail.Events {
+//			handle(event)
+//		}
+//		cursor = tail.NextCursor
+//	}
+//
+// Like Stream, the client timeout is not applied, so a poll isn't cut
+// short; ctx alone bounds it. Failed polls are not retried.
+func (s *TelemetryService) Tail(ctx context.Context, agentID, sinceCursor string) (*TelemetryTail, error) {
+	query := url.Values{}
+	if sinceCursor != "" {
+		query.Set("cursor", sinceCursor)
+	}
+	endpoint := withQuery(fmt.Sprintf("agents/%s/telemetry/tail", agentID), query)
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+
+	resp, _, err := s.client.openStream(ctx, endpoint, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	tail := &TelemetryTail{NextCursor: sinceCursor}
+	if resp.StatusCode == http.StatusNoContent {
+		return tail, nil
+	}
+	if err := s.client.newDecoder(resp.Body).Decode(tail); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if tail.NextCursor == "" {
+		tail.NextCursor = sinceCursor
+	}
+	return tail, nil
+}
+
+// openStream sends a GET to endpoint for a long-lived or large response,
+// adding header to the common headers, and returns the response once its
+// status is known to be successful. The caller must close the body. On
+// failure it reports whether the request is worth retrying.
+func (c *Client) openStream(ctx context.Context, endpoint string, header http.Header) (*http.Response, bool, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, false, fmt.Errorf("rate limiter: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s", c.baseURL, endpoint), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setHeaders(req)
+	if err := c.authorize(ctx, req); err != nil {
+		return nil, false, err
+	}
+	for key, values := range headerFrom(ctx) {
+		req.Header[key] = values
+	}
+	if id := c.correlationID(ctx); id != "" {
+		req.Header.Set(correlationIDHeader, id)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, false, err
+	}
+
+	// The body may take arbitrarily long to read, so unlike request the
+	// client timeout is not applied; ctx alone bounds it
+	resp, err := c.send(c.httpClient, req)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.breaker.record(true)
+		} else {
+			c.breaker.release()
+		}
+		return nil, true, fmt.Errorf("request failed: %w", err)
+	}
+	c.breaker.record(resp.StatusCode >= 500)
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, isRetryableStatus(resp.StatusCode), c.handleErrorResponse(resp)
+	}
+	return resp, false, nil
+}
+
+// stream runs a single SSE connection to endpoint, sending decoded events
+// until the connection ends. It reports whether the connection was
+// established and whether a failure is worth reconnecting after.
+func (c *Client) stream(ctx context.Context, endpoint string, lastEventID *string, events chan<- *TelemetryEvent) (bool, bool, error) {
+	header := http.Header{}
+	header.Set("Accept", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	if *lastEventID != "" {
+		header.Set("Last-Event-ID", *lastEventID)
+	}
+
+	resp, retry, err := c.openStream(ctx, endpoint, header)
+	if err != nil {
+		return false, retry, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line dispatches the buffered event
+			if data.Len() == 0 {
+				continue
+			}
+			var event TelemetryEvent
+			err := c.unmarshal([]byte(data.String()), &event)
+			data.Reset()
+			if err != nil {
+				// One bad event shouldn't end the stream; skip it
+				if c.logger != nil {
+					c.logger.Warn("agentmesh: skipping malformed stream event", "endpoint", endpoint, "event_id", *lastEventID, "error", err)
+				}
+				continue
+			}
+			select {
+			case events <- &event:
+			case <-ctx.Done():
+				return true, false, ctx.Err()
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment or keep-alive
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "data":
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(value)
+			case "id":
+				*lastEventID = value
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return true, true, fmt.Errorf("stream interrupted: %w", err)
+	}
+	return true, true, nil
+}
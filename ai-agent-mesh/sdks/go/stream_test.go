@@ -0,0 +1,146 @@
+package agentmesh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseServer serves body to the first stream connection and holds later
+// reconnections open, recording the Last-Event-ID each was sent with
+type sseServer struct {
+	*httptest.Server
+	mu           sync.Mutex
+	connections  int
+	lastEventIDs []string
+}
+
+func newSSEServer(t *testing.T, body string) *sseServer {
+	s := &sseServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.connections++
+		first := s.connections == 1
+		s.lastEventIDs = append(s.lastEventIDs, r.Header.Get("Last-Event-ID"))
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if first {
+			w.Write([]byte(body))
+			return
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// collectEvents streams from srv until want events arrive or a second
+// passes, and returns their IDs
+func collectEvents(t *testing.T, srv *sseServer, want int) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithBackoff(time.Millisecond, time.Millisecond, 1, false))
+	events, errs := client.Telemetry.Stream(ctx, "agent_1", nil)
+
+	var ids []string
+	for event := range events {
+		ids = append(ids, event.ID)
+		if len(ids) == want {
+			cancel()
+		}
+	}
+	for err := range errs {
+		t.Errorf("unexpected stream error: %v", err)
+	}
+	return ids
+}
+
+func TestStreamParsing(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "single event",
+			body: "data: {\"id\":\"e1\"}\n\n",
+			want: []string{"e1"},
+		},
+		{
+			name: "several events",
+			body: "data: {\"id\":\"e1\"}\n\ndata: {\"id\":\"e2\"}\n\n",
+			want: []string{"e1", "e2"},
+		},
+		{
+			name: "data split across lines",
+			body: "data: {\"id\":\ndata: \"e1\"}\n\n",
+			want: []string{"e1"},
+		},
+		{
+			name: "no space after colon",
+			body: "data:{\"id\":\"e1\"}\n\n",
+			want: []string{"e1"},
+		},
+		{
+			name: "comments and other fields ignored",
+			body: ": keep-alive\n\nevent: telemetry\nretry: 1000\ndata: {\"id\":\"e1\"}\n\n",
+			want: []string{"e1"},
+		},
+		{
+			name: "malformed event skipped",
+			body: "data: {\"id\":\"e1\"}\n\ndata: {not json\n\ndata: {\"id\":\"e2\"}\n\n",
+			want: []string{"e1", "e2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newSSEServer(t, tt.body)
+			assert.Equal(t, tt.want, collectEvents(t, srv, len(tt.want)))
+		})
+	}
+}
+
+func TestStreamResumesFromLastEventID(t *testing.T) {
+	srv := newSSEServer(t, "id: 41\ndata: {\"id\":\"e1\"}\n\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithBackoff(time.Millisecond, time.Millisecond, 1, false))
+	events, _ := client.Telemetry.Stream(ctx, "agent_1", nil)
+
+	event := <-events
+	require.Equal(t, "e1", event.ID)
+	require.Eventually(t, func() bool {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return srv.connections >= 2
+	}, time.Second, time.Millisecond)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Equal(t, []string{"", "41"}, srv.lastEventIDs[:2])
+}
+
+func TestOpenStreamCircuitBreaker(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", WithBaseURL(srv.URL), WithCircuitBreaker(1, time.Minute))
+	_, err := client.Telemetry.Tail(context.Background(), "agent_1", "")
+	require.ErrorIs(t, err, ErrServer)
+
+	_, err = client.Telemetry.Tail(context.Background(), "agent_1", "")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 1, hits)
+}
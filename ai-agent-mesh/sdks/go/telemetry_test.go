@@ -0,0 +1,54 @@
+package agentmesh
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateInterval(t *testing.T) {
+	var gotInterval string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotInterval = r.URL.Query().Get("interval")
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	client := NewClient("test-key", WithBaseURL(srv.URL))
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     string
+		wantErr  bool
+	}{
+		{"none", 0, "", false},
+		{"minute", time.Minute, "60", false},
+		{"one second", time.Second, "1", false},
+		{"sub-second", 500 * time.Millisecond, "", true},
+		{"negative", -time.Minute, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInterval = ""
+			_, err := client.Telemetry.Aggregate(context.Background(), "agent_1", &AggregateOptions{
+				Metric:   "latency_ms",
+				Function: AggregateAvg,
+				Interval: tt.interval,
+			})
+			if tt.wantErr {
+				var validationErr *ValidationError
+				require.True(t, errors.As(err, &validationErr), "got %v", err)
+				assert.Contains(t, validationErr.Fields, "interval")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, gotInterval)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package agentmesh
+
+import (
+	"strings"
+	"time"
+)
+
+// Service identifies one of the client's resource services, for settings
+// that vary between them
+type Service string
+
+// Services of the client
+const (
+	ServiceAgents      Service = "agents"
+	ServiceWorkflows   Service = "workflows"
+	ServicePolicies    Service = "policies"
+	ServiceTelemetry   Service = "telemetry"
+	ServiceFederation  Service = "federation"
+	ServiceMarketplace Service = "marketplace"
+	ServiceAccount     Service = "account"
+	ServiceWebhooks    Service = "webhooks"
+)
+
+// WithServiceTimeout overrides the timeout set with WithTimeout for the
+// requests of one service, e.g. a long one for workflow execution and a
+// short one for account reads. As with WithTimeout, it applies to each
+// attempt whose context has no deadline.
+func WithServiceTimeout(service Service, timeout time.Duration) Option {
+	return func(c *Config) {
+		if c.ServiceTimeouts == nil {
+			c.ServiceTimeouts = map[Service]time.Duration{}
+		}
+		c.ServiceTimeouts[service] = timeout
+	}
+}
+
+// timeoutFor returns the timeout of a request to endpoint, relative to the
+// base URL
+func (c *Client) timeoutFor(endpoint string) time.Duration {
+	if timeout, ok := c.serviceTimeouts[serviceOf(endpoint)]; ok {
+		return timeout
+	}
+	return c.timeout
+}
+
+// serviceOf returns the service whose methods call endpoint. Policy and
+// telemetry endpoints nested under an agent belong to those services, not
+// to ServiceAgents.
+func serviceOf(endpoint string) Service {
+	path, _, _ := strings.Cut(endpoint, "?")
+	parts := strings.Split(path, "/")
+	switch parts[0] {
+	case "agents", "agent-types":
+		if len(parts) > 2 {
+			switch parts[2] {
+			case "policies", "compliance":
+				return ServicePolicies
+			case "telemetry", "health":
+				return ServiceTelemetry
+			}
+		}
+		return ServiceAgents
+	case "workflows", "executions":
+		return ServiceWorkflows
+	}
+	return Service(parts[0])
+}
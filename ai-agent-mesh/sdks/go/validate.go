@@ -0,0 +1,73 @@
+package agentmesh
+
+import "fmt"
+
+// fieldErrors collects per-field validation failures
+type fieldErrors map[string]string
+
+func (f fieldErrors) require(field string, ok bool) {
+	if !ok {
+		f[field] = "is required"
+	}
+}
+
+// err returns a *ValidationError for the collected failures, or nil
+func (f fieldErrors) err(what string) error {
+	if len(f) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		Message: fmt.Sprintf("invalid %s", what),
+		Fields:  f,
+	}
+}
+
+// Validate checks that the required fields are set
+func (r *CreateAgentRequest) Validate() error {
+	if r == nil {
+		return &ValidationError{Message: "create agent request is nil"}
+	}
+	f := fieldErrors{}
+	f.require("name", r.Name != "")
+	f.require("type", r.Type != "")
+	return f.err("create agent request")
+}
+
+// Validate checks that the required fields are set
+func (r *CreateWorkflowRequest) Validate() error {
+	if r == nil {
+		return &ValidationError{Message: "create workflow request is nil"}
+	}
+	f := fieldErrors{}
+	f.require("agent_id", r.AgentID != "")
+	f.require("definition", len(r.Definition) > 0)
+	return f.err("create workflow request")
+}
+
+// Validate checks that the required fields are set
+func (r *ApplyPolicyRequest) Validate() error {
+	if r == nil {
+		return &ValidationError{Message: "apply policy request is nil"}
+	}
+	f := fieldErrors{}
+	f.require("name", r.Name != "")
+	f.require("framework", r.Framework != "")
+	return f.err("apply policy request")
+}
+
+// Validate checks that the required fields are set
+func (r *PublishPolicyRequest) Validate() error {
+	if r == nil {
+		return &ValidationError{Message: "publish policy request is nil"}
+	}
+	f := fieldErrors{}
+	f.require("name", r.Name != "")
+	f.require("description", r.Description != "")
+	f.require("framework", r.Framework != "")
+	f.require("category", r.Category != "")
+	f.require("rules", len(r.Rules) > 0)
+	if r.Visibility != "" && r.Visibility != VisibilityDraft && r.Visibility != VisibilityPublic {
+		f["visibility"] = fmt.Sprintf("must be %q or %q", VisibilityDraft, VisibilityPublic)
+	}
+	return f.err("publish policy request")
+}
@@ -0,0 +1,125 @@
+package agentmesh
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Webhook event types
+const (
+	EventAgentStatusChanged  = "agent.status_changed"
+	EventComplianceViolation = "compliance.violation"
+	EventWorkflowCompleted   = "workflow.completed"
+)
+
+// SignatureHeader is the header carrying the HMAC signature of a webhook
+// delivery
+const SignatureHeader = "X-AgentMesh-Signature"
+
+// TimestampHeader is the header carrying the Unix time, in seconds, at
+// which a webhook delivery was signed
+const TimestampHeader = "X-AgentMesh-Timestamp"
+
+// DefaultSignatureTolerance is how far a delivery's timestamp may be from
+// the current time for VerifyTimestampedSignature to accept it
+const DefaultSignatureTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned by VerifySignature when a delivery's
+	// signature doesn't match its payload
+	ErrInvalidSignature = errors.New("agentmesh: invalid webhook signature")
+	// ErrSignatureExpired is returned by VerifyTimestampedSignature when a
+	// delivery was signed too long ago, or in the future, to be trusted
+	ErrSignatureExpired = errors.New("agentmesh: webhook signature expired")
+)
+
+// WebhookService handles webhook subscriptions
+type WebhookService struct {
+	client *Client
+}
+
+// Create subscribes url to the given event types. The returned webhook's
+// Secret is only available here; keep it to verify deliveries.
+func (s *WebhookService) Create(ctx context.Context, url string, events []string) (*Webhook, error) {
+	var webhook Webhook
+	req := map[string]interface{}{"url": url, "events": events}
+	err := s.client.request(ctx, http.MethodPost, "webhooks", req, &webhook)
+	return &webhook, err
+}
+
+// List retrieves all webhook subscriptions
+func (s *WebhookService) List(ctx context.Context) ([]*Webhook, error) {
+	var webhooks []*Webhook
+	err := s.client.request(ctx, http.MethodGet, "webhooks", nil, &webhooks)
+	return webhooks, err
+}
+
+// Delete removes a webhook subscription
+func (s *WebhookService) Delete(ctx context.Context, webhookID string) error {
+	return s.client.request(ctx, http.MethodDelete, fmt.Sprintf("webhooks/%s", webhookID), nil, nil)
+}
+
+// VerifySignature checks that signature, the value of the SignatureHeader
+// of a delivery, is the HMAC-SHA256 of payload under the webhook's secret.
+// payload must be the raw request body, before any decoding.
+//
+//	payload, _ := io.ReadAll(r.Body)
+//	if err := agentmesh.VerifySignature(payload, r.Header.Get(agentmesh.SignatureHeader), secret); err != nil {
+//		http.Error(w, "invalid signature", http.StatusUnauthorized)
+//		return
+//	}
+func VerifySignature(payload []byte, signature, secret string) error {
+	return verifyHMAC(payload, signature, secret)
+}
+
+// VerifyTimestampedSignature is like VerifySignature for deliveries that
+// also carry a TimestampHeader, whose signature covers the timestamp, a
+// ".", and then payload. It additionally rejects deliveries signed more
+// than tolerance before or after now with ErrSignatureExpired, so that a
+// captured delivery can't be replayed later. A tolerance of zero or less
+// means DefaultSignatureTolerance.
+func VerifyTimestampedSignature(payload []byte, signature, timestamp, secret string, tolerance time.Duration) error {
+	if err := verifyHMAC([]byte(timestamp+"."+string(payload)), signature, secret); err != nil {
+		return err
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	age := time.Since(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// verifyHMAC checks that signature is "sha256=" followed by the hex
+// HMAC-SHA256 of message under secret
+func verifyHMAC(message []byte, signature, secret string) error {
+	encoded, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(message)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
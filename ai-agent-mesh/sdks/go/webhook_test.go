@@ -0,0 +1,75 @@
+package agentmesh
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sign returns the SignatureHeader value of message under secret
+func sign(message, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "whsec_test"
+	payload := `{"type":"agent.status_changed","agentId":"agent_1"}`
+
+	tests := []struct {
+		name      string
+		payload   string
+		signature string
+		want      error
+	}{
+		{"valid", payload, sign(payload, secret), nil},
+		{"tampered payload", `{"type":"agent.status_changed","agentId":"agent_2"}`, sign(payload, secret), ErrInvalidSignature},
+		{"wrong secret", payload, sign(payload, "whsec_other"), ErrInvalidSignature},
+		{"missing prefix", payload, sign(payload, secret)[len("sha256="):], ErrInvalidSignature},
+		{"not hex", payload, "sha256=zz", ErrInvalidSignature},
+		{"empty", payload, "", ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, VerifySignature([]byte(tt.payload), tt.signature, secret))
+		})
+	}
+}
+
+func TestVerifyTimestampedSignature(t *testing.T) {
+	const secret = "whsec_test"
+	payload := `{"type":"workflow.completed"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		payload   string
+		signature string
+		timestamp string
+		tolerance time.Duration
+		want      error
+	}{
+		{"valid", payload, sign(now+"."+payload, secret), now, 0, nil},
+		{"tampered payload", `{"type":"agent.status_changed"}`, sign(now+"."+payload, secret), now, 0, ErrInvalidSignature},
+		{"tampered timestamp", payload, sign(stale+"."+payload, secret), now, 0, ErrInvalidSignature},
+		{"signature without timestamp", payload, sign(payload, secret), now, 0, ErrInvalidSignature},
+		{"stale timestamp", payload, sign(stale+"."+payload, secret), stale, 0, ErrSignatureExpired},
+		{"future timestamp", payload, sign(future+"."+payload, secret), future, 0, ErrSignatureExpired},
+		{"stale within tolerance", payload, sign(stale+"."+payload, secret), stale, time.Hour, nil},
+		{"malformed timestamp", payload, sign("soon."+payload, secret), "soon", 0, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyTimestampedSignature([]byte(tt.payload), tt.signature, tt.timestamp, secret, tt.tolerance)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}